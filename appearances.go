@@ -0,0 +1,88 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"regexp"
+	"strings"
+)
+
+// What the source says about a character's appearance in an issue.
+type AppearanceHint int
+
+// Hints for appearances.
+const (
+	AppearanceNone  AppearanceHint = iota // The source doesn't say anything about the appearance.
+	AppearanceFirst                       // The character's first appearance, such as "(First Appearance)" or "(1st app.)".
+	AppearanceCameo                       // A brief appearance, such as "(Cameo)".
+	AppearanceDeath                       // The character dies in the issue, such as "(Death)".
+)
+
+var appearanceHintNames = [...]string{"", "first appearance", "cameo", "death"}
+
+// The note in parentheses after a character's link, such as ` (First Appearance)`.
+var regAppearanceNote = regexp.MustCompile(`^\s*\(([^)]+)\)`)
+
+// Gets the name of the hint, such as `first appearance`, or an empty string for `AppearanceNone`.
+func (h AppearanceHint) String() string {
+	if h < 0 || int(h) >= len(appearanceHintNames) {
+		return appearanceHintNames[AppearanceNone]
+	}
+	return appearanceHintNames[h]
+}
+
+// Parses the note about an appearance, such as "First Appearance", "1st app." or "cameo".
+// Notes that aren't about the kind of appearance are `AppearanceNone`.
+func ParseAppearanceHint(note string) AppearanceHint {
+	note = strings.ToLower(note)
+	switch {
+	case strings.Contains(note, "first app"), strings.Contains(note, "1st app"):
+		return AppearanceFirst
+	case strings.Contains(note, "cameo"):
+		return AppearanceCameo
+	case strings.Contains(note, "death"), strings.Contains(note, "dies"):
+		return AppearanceDeath
+	}
+	return AppearanceNone
+}
+
+// Gets the characters from cb's "Characters:" lists in the section, for the whole issue and for each of its stories.
+// Characters in more than one list are only returned once, with the first hint that was found for them.
+func (p *CbParser) characters(section *goquery.Selection) []CharacterLink {
+	characterLinks := make([]CharacterLink, 0)
+	indexes := make(map[string]int)
+	inCharacters := false
+	section.Find("strong, a").Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "strong" {
+			// Other labels, such as "Groups:", end the list of characters.
+			inCharacters = strings.TrimSpace(s.Text()) == "Characters:"
+			return
+		}
+		hrefValue, ex := s.Attr("href")
+		if !inCharacters || !ex || !strings.HasPrefix(hrefValue, "character.php?ID=") {
+			return
+		}
+		characterLink := p.characterLink(s, hrefValue)
+		if index, ok := indexes[characterLink.Url]; ok {
+			if characterLinks[index].Appearance == AppearanceNone {
+				characterLinks[index].Appearance = characterLink.Appearance
+			}
+			return
+		}
+		indexes[characterLink.Url] = len(characterLinks)
+		characterLinks = append(characterLinks, characterLink)
+	})
+	return characterLinks
+}
+
+// Gets the character from its link with the hint in the note after it, such as ` (Cameo)`.
+func (p *CbParser) characterLink(s *goquery.Selection, hrefValue string) CharacterLink {
+	characterLink := CharacterLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue), Name: strings.TrimSpace(s.Text())}
+	if next := s.Nodes[0].NextSibling; next != nil && next.Type == html.TextNode {
+		if match := regAppearanceNote.FindStringSubmatch(next.Data); match != nil {
+			characterLink.Appearance = ParseAppearanceHint(match[1])
+		}
+	}
+	return characterLink
+}
@@ -0,0 +1,21 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseAppearanceHint(t *testing.T) {
+	for note, expected := range map[string]AppearanceHint{
+		"First Appearance": AppearanceFirst,
+		"1st app.":         AppearanceFirst,
+		"Cameo":            AppearanceCameo,
+		"cameo, flashback": AppearanceCameo,
+		"Death":            AppearanceDeath,
+		"dies":             AppearanceDeath,
+		"Earth-616":        AppearanceNone,
+		"":                 AppearanceNone,
+	} {
+		assert.Equal(t, expected, ParseAppearanceHint(note), note)
+	}
+}
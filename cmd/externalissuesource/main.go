@@ -0,0 +1,299 @@
+// Command externalissuesource searches, fetches and parses entities from the cb source and prints them
+// as JSON or a table. It's handy for checking how `CbParser` sees a page without writing a throwaway main.
+//
+//	externalissuesource search cyclops
+//	externalissuesource search --type title "astonishing x-men"
+//	externalissuesource search --prefer Marvel cyclops
+//	externalissuesource -format json character "http://comicbookdb.com/character.php?ID=82321"
+//	externalissuesource issue "http://comicbookdb.com/issue.php?ID=338389"
+//	externalissuesource series "http://comicbookdb.com/title.php?ID=439"
+//	externalissuesource publisher "http://comicbookdb.com/publisher.php?ID=4"
+//	externalissuesource storyarc "http://comicbookdb.com/storyarc.php?ID=2002"
+//	externalissuesource parse-file --kind issue ./testdata/cb_issue.html
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/aimeelaplant/externalissuesource"
+	"github.com/aimeelaplant/externalissuesource/httpcache"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+const usage = `Usage: externalissuesource [flags] <command> [args]
+
+Commands:
+  search [--type title|creator|publisher|storyarc|character] [--prefer <publishers>] <query>
+                                                  Search the entities by name. Default type is character.
+                                                  Characters are ranked against the query with --prefer.
+  character <url>                                 Fetch a character with all of its issues.
+  character-page <url>                            Fetch a character page without its issues.
+  issue <url>                                     Fetch an issue.
+  series <url>                                    Fetch a series (title) with the links to its issues.
+  publisher <url>                                 Fetch a publisher with its imprints and the links to its series.
+  storyarc <url>                                  Fetch a story arc with the links to its issues in reading order.
+  parse-file --kind issue|character|search|series|publisher|storyarc [--type <search type>] <path>
+                                                  Parse a saved cb page.
+
+Flags:
+`
+
+var errUsage = errors.New("invalid usage")
+
+// The search types for the `--type` flag of the search command and parse-file.
+var searchTypes = map[string]externalissuesource.SearchType{
+	"title":     externalissuesource.SearchTitle,
+	"creator":   externalissuesource.SearchCreator,
+	"publisher": externalissuesource.SearchPublisher,
+	"storyarc":  externalissuesource.SearchStoryArc,
+	"character": externalissuesource.SearchCharacter,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
+
+// Parses the flags and runs the command. The results are written to stdout and usage errors to stderr.
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	config := &externalissuesource.CbExternalSourceConfig{Logger: log.New(stderr, "", 0)}
+	var format, cacheDir, coversDir string
+	var retries int
+	var retryBackoff time.Duration
+
+	flags := flag.NewFlagSet("externalissuesource", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&format, "format", "table", "The output format: table or json.")
+	flags.StringVar(&config.SessionId, "session-id", os.Getenv("CB_SESSION_ID"), "The PHPSESSID cookie for issue pages. Default is $CB_SESSION_ID.")
+	flags.StringVar(&config.CbOne, "cb-one", os.Getenv("CB_ONE"), "The cbdb1 cookie for issue pages. Default is $CB_ONE.")
+	flags.StringVar(&config.CbTwo, "cb-two", os.Getenv("CB_TWO"), "The cbdb2 cookie for issue pages. Default is $CB_TWO.")
+	flags.IntVar(&config.IssueWorkers, "issue-workers", 5, "The number of issues to fetch concurrently for a character.")
+	flags.Float64Var(&config.RequestsPerSecond, "requests-per-second", 1, "The number of requests per second sent to the site.")
+	flags.IntVar(&config.Burst, "burst", 5, "The number of requests that can be sent at once before being limited.")
+	flags.DurationVar(&config.Jitter, "jitter", 0, "The maximum random delay added before each request.")
+	flags.BoolVar(&config.DisableRateLimit, "no-rate-limit", false, "Send requests as fast as they're made.")
+	flags.IntVar(&retries, "retries", externalissuesource.DefaultRetryPolicy.MaxAttempts-1, "The number of times a failed request is retried.")
+	flags.DurationVar(&retryBackoff, "retry-backoff", externalissuesource.DefaultRetryPolicy.InitialBackoff, "The wait before the first retry. It doubles after each retry.")
+	flags.StringVar(&cacheDir, "cache-dir", "", "Cache the pages in the directory. Pages aren't cached if not provided.")
+	flags.StringVar(&coversDir, "covers-dir", "", "Download the covers of a fetched issue into the directory. Covers aren't downloaded if not provided.")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if format != "table" && format != "json" {
+		fmt.Fprintf(stderr, "unknown format %q\n", format)
+		return errUsage
+	}
+	retry := externalissuesource.DefaultRetryPolicy
+	retry.MaxAttempts = retries + 1
+	retry.InitialBackoff = retryBackoff
+	config.Retry = &retry
+	if cacheDir != "" {
+		cache, err := httpcache.NewDiskCache(cacheDir)
+		if err != nil {
+			return err
+		}
+		config.Cache = cache
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+	if command == "parse-file" {
+		return parseFile(commandArgs, format, stdout, stderr)
+	}
+	var search searchOptions
+	if command == "search" {
+		var err error
+		if search, commandArgs, err = parseSearchFlags(commandArgs, stderr); err != nil {
+			return err
+		}
+	}
+	if len(commandArgs) != 1 {
+		flags.Usage()
+		return errUsage
+	}
+	source := externalissuesource.NewCbExternalSource(externalissuesource.NewHttpClient(), config)
+	switch command {
+	case "search":
+		result, err := source.SearchContext(ctx, strings.TrimSpace(commandArgs[0]), search.searchType)
+		if err != nil {
+			return err
+		}
+		if len(search.publishers) > 0 {
+			result.Characters = externalissuesource.RankCharacters(commandArgs[0], result.Characters, search.publishers...)
+		}
+		return write(stdout, format, &result)
+	case "character":
+		character, err := source.CharacterContext(ctx, commandArgs[0])
+		if character == nil {
+			return err
+		}
+		// Still print the issues that were fetched when some of them failed.
+		if writeErr := write(stdout, format, character); writeErr != nil {
+			return writeErr
+		}
+		return err
+	case "character-page":
+		characterPage, err := source.CharacterPageContext(ctx, commandArgs[0])
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, characterPage)
+	case "issue":
+		issue, err := source.IssueContext(ctx, commandArgs[0])
+		if err != nil {
+			return err
+		}
+		if downloader, ok := source.(externalissuesource.CoverDownloader); ok && coversDir != "" {
+			covers, err := downloader.DownloadCoversContext(ctx, issue, coversDir)
+			for _, cover := range covers {
+				fmt.Fprintf(stderr, "saved %s cover to %s\n", cover.Cover.Kind, cover.Path)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return write(stdout, format, issue)
+	case "series":
+		seriesPage, err := source.SeriesContext(ctx, commandArgs[0])
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, seriesPage)
+	case "publisher":
+		publisher, err := source.PublisherContext(ctx, commandArgs[0])
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, publisher)
+	case "storyarc":
+		storyArc, err := source.StoryArcContext(ctx, commandArgs[0])
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, storyArc)
+	}
+	fmt.Fprintf(stderr, "unknown command %q\n", command)
+	flags.Usage()
+	return errUsage
+}
+
+// The options from the search command's flags.
+type searchOptions struct {
+	searchType externalissuesource.SearchType
+	publishers []string // The preferred publishers for ranking the characters, or none to keep the source's order.
+}
+
+// Parses the search command's flags and returns the options with the remaining args.
+func parseSearchFlags(args []string, stderr io.Writer) (searchOptions, []string, error) {
+	var typeName, prefer string
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&typeName, "type", "character", "The kind of entity: title, creator, publisher, storyarc or character.")
+	flags.StringVar(&prefer, "prefer", "", "Rank the characters against the query, preferring the comma-separated publishers in order, such as Marvel,DC.")
+	if err := flags.Parse(args); err != nil {
+		return searchOptions{}, nil, errUsage
+	}
+	searchType, ok := searchTypes[typeName]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown search type %q\n", typeName)
+		return searchOptions{}, nil, errUsage
+	}
+	options := searchOptions{searchType: searchType}
+	for _, publisher := range strings.Split(prefer, ",") {
+		if publisher = strings.TrimSpace(publisher); publisher != "" {
+			options.publishers = append(options.publishers, publisher)
+		}
+	}
+	return options, flags.Args(), nil
+}
+
+// Parses a saved page with `CbParser`, such as one from testdata.
+func parseFile(args []string, format string, stdout io.Writer, stderr io.Writer) error {
+	var kind, typeName string
+	flags := flag.NewFlagSet("parse-file", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&kind, "kind", "issue", "The kind of page: issue, character, search, series, publisher or storyarc.")
+	flags.StringVar(&typeName, "type", "character", "The kind of entity a search page is for: title, creator, publisher, storyarc or character.")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "Usage: externalissuesource parse-file --kind issue|character|search|series|publisher|storyarc [--type <search type>] <path>")
+		return errUsage
+	}
+	searchType, ok := searchTypes[typeName]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown search type %q\n", typeName)
+		return errUsage
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	parser := &externalissuesource.CbParser{}
+	switch kind {
+	case "issue":
+		result, err := parser.IssueResult(file)
+		if err != nil {
+			return err
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", warning)
+		}
+		return write(stdout, format, result.Issue)
+	case "character":
+		characterPage, err := parser.Character(file)
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, characterPage)
+	case "search":
+		result, err := parser.Search(file, searchType)
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, result)
+	case "series":
+		seriesPage, err := parser.Series(file)
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, seriesPage)
+	case "publisher":
+		publisher, err := parser.Publisher(file)
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, publisher)
+	case "storyarc":
+		storyArc, err := parser.StoryArc(file)
+		if err != nil {
+			return err
+		}
+		return write(stdout, format, storyArc)
+	}
+	fmt.Fprintf(stderr, "unknown kind %q\n", kind)
+	return errUsage
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/aimeelaplant/externalissuesource"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRun_ParseFileIssue(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"parse-file", "--kind", "issue", "../../testdata/cb_issue.html"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), "Astonishing X-Men (2004)")
+	assert.Contains(t, stdout.String(), "Format:            Standard")
+	assert.Contains(t, stdout.String(), "Publication date:  2007-10-01")
+
+	stdout.Reset()
+	err = run(context.Background(), []string{"-format", "json", "parse-file", "--kind", "issue", "../../testdata/cb_issue.html"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	issue := new(externalissuesource.Issue)
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), issue))
+	assert.Equal(t, "22", issue.Number)
+	assert.Equal(t, "Marvel", issue.Vendor)
+}
+
+func TestRun_ParseFileWarnings(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"parse-file", "--kind", "issue", "../../testdata/cb_issue_warnings.html"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), "Astonishing X-Men (2004)")
+	assert.Contains(t, stderr.String(), `warning: can't parse cover date "Fall/Winter 2007"`)
+}
+
+func TestRun_ParseFileSearch(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"parse-file", "--kind", "search", "../../testdata/cyclops/search.html"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 47)
+	assert.Contains(t, string(lines[0]), "NAME")
+	assert.Contains(t, string(lines[1]), "http://comicbookdb.com/character.php?ID=")
+}
+
+func TestRun_ParseFileSearchTitle(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"parse-file", "--kind", "search", "--type", "title", "../../testdata/cb_search_title.html"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 5)
+	assert.Contains(t, string(lines[0]), "PUBLISHER")
+	assert.Contains(t, string(lines[2]), "2004")
+	assert.Contains(t, string(lines[2]), "Marvel")
+}
+
+func TestRun_ParseFileSeries(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"parse-file", "--kind", "series", "../../testdata/cb_title.html"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), "Astonishing X-Men")
+	assert.Contains(t, stdout.String(), "Wolverine Cover")
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, errUsage, run(context.Background(), []string{}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage:")
+	assert.Equal(t, errUsage, run(context.Background(), []string{"-format", "xml", "search", "cyclops"}, &stdout, &stderr))
+	assert.Equal(t, errUsage, run(context.Background(), []string{"issue"}, &stdout, &stderr))
+	assert.Equal(t, errUsage, run(context.Background(), []string{"comic", "x"}, &stdout, &stderr))
+	assert.Equal(t, errUsage, run(context.Background(), []string{"parse-file", "--kind", "creator", "../../testdata/cb_issue.html"}, &stdout, &stderr))
+	assert.Equal(t, errUsage, run(context.Background(), []string{"search", "--type", "group", "x-men"}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
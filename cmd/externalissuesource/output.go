@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aimeelaplant/externalissuesource"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Writes the entity as indented JSON or as a table.
+func write(w io.Writer, format string, v interface{}) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	switch entity := v.(type) {
+	case *externalissuesource.Issue:
+		writeIssue(tw, entity)
+	case *externalissuesource.Character:
+		fmt.Fprintf(tw, "Name:\t%s\n", entity.Name)
+		fmt.Fprintf(tw, "Publisher:\t%s\n", entity.Publisher)
+		writeLinks(tw, "Other identities:", entity.OtherIdentities)
+		fmt.Fprintln(tw)
+		writeIssues(tw, entity.Issues)
+	case *externalissuesource.CharacterPage:
+		fmt.Fprintf(tw, "Name:\t%s\n", entity.Name)
+		fmt.Fprintf(tw, "Other name:\t%s\n", entity.OtherName)
+		fmt.Fprintf(tw, "Publisher:\t%s\n", entity.Publisher)
+		writeLinks(tw, "Other identities:", entity.OtherIdentities)
+		fmt.Fprintf(tw, "Issues:\t%d\n", len(entity.IssueLinks))
+		for _, link := range entity.IssueLinks {
+			fmt.Fprintf(tw, "\t%s\n", link)
+		}
+	case *externalissuesource.SeriesPage:
+		fmt.Fprintf(tw, "ID:\t%s\n", entity.Id)
+		fmt.Fprintf(tw, "Name:\t%s\n", entity.Name)
+		fmt.Fprintf(tw, "Start year:\t%d\n", entity.StartYear)
+		fmt.Fprintf(tw, "Publisher:\t%s\n", entity.Publisher)
+		fmt.Fprintf(tw, "Volume:\t%s\n", entity.Volume)
+		fmt.Fprintf(tw, "Format:\t%s\n", entity.Format)
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "NUMBER\tVARIANT\tURL")
+		for _, link := range entity.IssueLinks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", link.Number, link.VariantName, link.Url)
+		}
+	case *externalissuesource.Publisher:
+		fmt.Fprintf(tw, "ID:\t%s\n", entity.Id)
+		fmt.Fprintf(tw, "Name:\t%s\n", entity.Name)
+		if entity.Parent != nil {
+			fmt.Fprintf(tw, "Imprint of:\t%s (%s)\n", entity.Parent.Name, entity.Parent.Url)
+		}
+		fmt.Fprintf(tw, "Years:\t%s\n", years(entity.YearBegan, entity.YearEnded))
+		for i, imprint := range entity.Imprints {
+			label := ""
+			if i == 0 {
+				label = "Imprints:"
+			}
+			fmt.Fprintf(tw, "%s\t%s (%s)\n", label, imprint.Name, imprint.Url)
+		}
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SERIES\tYEAR\tURL")
+		for _, link := range entity.SeriesLinks {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", link.Name, link.StartYear, link.Url)
+		}
+	case *externalissuesource.StoryArc:
+		fmt.Fprintf(tw, "ID:\t%s\n", entity.Id)
+		fmt.Fprintf(tw, "Name:\t%s\n", entity.Name)
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "#\tSERIES\tNUMBER\tURL")
+		for i, link := range entity.IssueLinks {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, link.Series, link.Number, link.Url)
+		}
+	case *externalissuesource.SearchResult:
+		writeSearchResult(tw, entity)
+	case *externalissuesource.CharacterSearchResult:
+		fmt.Fprintln(tw, "NAME\tURL")
+		for _, result := range entity.Results {
+			fmt.Fprintf(tw, "%s\t%s\n", result.Name, result.Url)
+		}
+	default:
+		return fmt.Errorf("can't write %T as a table", v)
+	}
+	return tw.Flush()
+}
+
+func writeIssue(w io.Writer, issue *externalissuesource.Issue) {
+	fmt.Fprintf(w, "ID:\t%s\n", issue.Id)
+	fmt.Fprintf(w, "Series:\t%s\n", issue.Series)
+	fmt.Fprintf(w, "Series ID:\t%s\n", issue.SeriesId)
+	fmt.Fprintf(w, "Publisher:\t%s\n", issue.Vendor)
+	fmt.Fprintf(w, "Number:\t%s\n", issue.Number)
+	fmt.Fprintf(w, "Format:\t%s\n", issue.Format)
+	fmt.Fprintf(w, "Cover date:\t%s (%s)\n", issue.CoverDate, issue.CoverDate.Precision)
+	fmt.Fprintf(w, "Publication date:\t%s\n", formatDate(issue.PublicationDate))
+	fmt.Fprintf(w, "On sale date:\t%s\n", formatDate(issue.OnSaleDate))
+	fmt.Fprintf(w, "Month uncertain:\t%t\n", issue.MonthUncertain)
+	fmt.Fprintf(w, "Variant:\t%t\n", issue.IsVariant)
+	fmt.Fprintf(w, "Reprint:\t%t\n", issue.IsReprint)
+	writeVariantInfo(w, issue.VariantInfo)
+	if issue.CoverPrice != nil {
+		fmt.Fprintf(w, "Cover price:\t%d.%02d %s\n", issue.CoverPrice.Amount/100, issue.CoverPrice.Amount%100, issue.CoverPrice.Currency)
+	}
+	if issue.PageCount > 0 {
+		fmt.Fprintf(w, "Pages:\t%d\n", issue.PageCount)
+	}
+	if issue.UPC != "" {
+		fmt.Fprintf(w, "UPC:\t%s\n", issue.UPC)
+	}
+	if issue.ISBN != "" {
+		fmt.Fprintf(w, "ISBN:\t%s\n", issue.ISBN)
+	}
+	if issue.Rating != externalissuesource.RatingUnknown {
+		fmt.Fprintf(w, "Rating:\t%s\n", issue.Rating)
+	}
+	if issue.VersionOf != nil {
+		fmt.Fprintf(w, "Version of:\t%s (%s)\n", issue.VersionOf.Title, issue.VersionOf.Url)
+	}
+	for i, version := range issue.Versions {
+		label := ""
+		if i == 0 {
+			label = "Versions:"
+		}
+		fmt.Fprintf(w, "%s\t%s (%s)\n", label, version.Title, version.Url)
+	}
+	for i, cover := range issue.Covers {
+		label := ""
+		if i == 0 {
+			label = "Covers:"
+		}
+		fmt.Fprintf(w, "%s\t%s (%s)\n", label, cover.Url, cover.Kind)
+	}
+	for i, story := range issue.Stories {
+		label := ""
+		if i == 0 {
+			label = "Stories:"
+		}
+		details := fmt.Sprintf("%d. %s", i+1, story.Title)
+		if story.PageCount > 0 {
+			details += fmt.Sprintf(" (%d pages)", story.PageCount)
+		}
+		if story.IsReprintOf != nil {
+			details += fmt.Sprintf(", reprinted from %s", story.IsReprintOf.Title)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", label, details)
+	}
+	for i, credit := range issue.Credits {
+		label := ""
+		if i == 0 {
+			label = "Credits:"
+		}
+		if credit.StoryIndex == externalissuesource.WholeIssue {
+			fmt.Fprintf(w, "%s\t%s: %s\n", label, credit.Role, credit.CreatorName)
+		} else {
+			fmt.Fprintf(w, "%s\t%s: %s (story %d)\n", label, credit.Role, credit.CreatorName, credit.StoryIndex+1)
+		}
+	}
+	for i, character := range issue.Characters {
+		label := ""
+		if i == 0 {
+			label = "Characters:"
+		}
+		if character.Appearance == externalissuesource.AppearanceNone {
+			fmt.Fprintf(w, "%s\t%s (%s)\n", label, character.Name, character.Url)
+		} else {
+			fmt.Fprintf(w, "%s\t%s (%s) [%s]\n", label, character.Name, character.Url, character.Appearance)
+		}
+	}
+	for i, storyArc := range issue.StoryArcs {
+		label := ""
+		if i == 0 {
+			label = "Story arcs:"
+		}
+		fmt.Fprintf(w, "%s\t%s (%s)\n", label, storyArc.Name, storyArc.Url)
+	}
+}
+
+func writeVariantInfo(w io.Writer, info externalissuesource.VariantInfo) {
+	if info.CoverLetter != "" {
+		fmt.Fprintf(w, "Cover:\t%s\n", info.CoverLetter)
+	}
+	if info.Printing > 0 {
+		fmt.Fprintf(w, "Printing:\t%d\n", info.Printing)
+	}
+	if info.Ratio > 0 {
+		fmt.Fprintf(w, "Ratio:\t1:%d\n", info.Ratio)
+	}
+	if info.Incentive {
+		fmt.Fprintln(w, "Incentive:\ttrue")
+	}
+	if info.RetailerExclusive {
+		fmt.Fprintln(w, "Retailer exclusive:\ttrue")
+	}
+	if info.Edition != externalissuesource.EditionUnknown {
+		fmt.Fprintf(w, "Edition:\t%s\n", info.Edition)
+	}
+	if info.DirectorsCut {
+		fmt.Fprintln(w, "Director's cut:\ttrue")
+	}
+}
+
+func writeIssues(w io.Writer, issues []externalissuesource.Issue) {
+	fmt.Fprintln(w, "ID\tSERIES\tNUMBER\tFORMAT\tPUBLICATION DATE\tON SALE DATE\tVARIANT\tREPRINT")
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\t%t\n", issue.Id, issue.Series, issue.Number, issue.Format,
+			formatDate(issue.PublicationDate), formatDate(issue.OnSaleDate), issue.IsVariant, issue.IsReprint)
+	}
+}
+
+// Formats the years a publisher was active, such as `1982 - 1996` or `1939 - present`.
+func years(began int, ended int) string {
+	if ended == 0 {
+		return fmt.Sprintf("%d - present", began)
+	}
+	return fmt.Sprintf("%d - %d", began, ended)
+}
+
+func writeLinks(w io.Writer, label string, links []externalissuesource.CharacterLink) {
+	if len(links) == 0 {
+		fmt.Fprintf(w, "%s\t\n", label)
+		return
+	}
+	for i, link := range links {
+		if i > 0 {
+			label = ""
+		}
+		fmt.Fprintf(w, "%s\t%s (%s)\n", label, link.Name, link.Url)
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// Writes the results for the search's type, with the year and publisher for the types that have them.
+func writeSearchResult(w io.Writer, result *externalissuesource.SearchResult) {
+	switch result.Type {
+	case externalissuesource.SearchTitle:
+		fmt.Fprintln(w, "NAME\tYEAR\tPUBLISHER\tURL")
+		for _, link := range result.Titles {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", link.Name, link.StartYear, link.Publisher, link.Url)
+		}
+	case externalissuesource.SearchCreator:
+		fmt.Fprintln(w, "NAME\tURL")
+		for _, link := range result.Creators {
+			fmt.Fprintf(w, "%s\t%s\n", link.Name, link.Url)
+		}
+	case externalissuesource.SearchPublisher:
+		fmt.Fprintln(w, "NAME\tURL")
+		for _, link := range result.Publishers {
+			fmt.Fprintf(w, "%s\t%s\n", link.Name, link.Url)
+		}
+	case externalissuesource.SearchStoryArc:
+		fmt.Fprintln(w, "NAME\tPUBLISHER\tURL")
+		for _, link := range result.StoryArcs {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", link.Name, link.Publisher, link.Url)
+		}
+	case externalissuesource.SearchCharacter:
+		fmt.Fprintln(w, "NAME\tPUBLISHER\tDISAMBIGUATION\tURL")
+		for _, link := range result.Characters {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", link.Name, link.Publisher, link.Disambiguation, link.Url)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package externalissuesource
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How precisely a cover date is known.
+type DatePrecision int
+
+// Precisions for cover dates, from the most to the least precise.
+const (
+	PrecisionUnknown   DatePrecision = iota // The date couldn't be parsed.
+	PrecisionDay                            // A day, such as "April 1 2009".
+	PrecisionMonth                          // A month, such as "October 2007" or "Mid December 1989".
+	PrecisionDualMonth                      // A range of months, such as "Jan/Feb 1972" or "Jul/Sep 1971".
+	PrecisionSeason                         // A season or part of the year, such as "Summer 1985" or "Early 1978".
+	PrecisionYear                           // Only the year, such as "1990" or "Annual 1992".
+)
+
+var datePrecisionNames = [...]string{"unknown", "day", "month", "dual-month", "season", "year"}
+
+// Gets the name of the precision, such as `season`.
+func (p DatePrecision) String() string {
+	if p < 0 || int(p) >= len(datePrecisionNames) {
+		return datePrecisionNames[PrecisionUnknown]
+	}
+	return datePrecisionNames[p]
+}
+
+// The months that each season or part of the year covers, from the first month to the last.
+// Winter is the winter that ends in the cover year, so "Winter 1985" is December 1984 to February 1985.
+var coverDateSeasons = map[string][2]time.Month{
+	"spring":  {time.March, time.May},
+	"summer":  {time.June, time.August},
+	"fall":    {time.September, time.November},
+	"autumn":  {time.September, time.November},
+	"winter":  {time.December, time.February},
+	"holiday": {time.November, time.December},
+	"early":   {time.January, time.April},
+	"mid":     {time.May, time.August},
+	"late":    {time.September, time.December},
+}
+
+// The date printed on an issue's cover, kept with its original text and how precisely it's known,
+// so "Summer 1985" isn't turned into a made-up month.
+type CoverDate struct {
+	Text      string        // The cover date as the source shows it, such as "Summer 1985".
+	Precision DatePrecision // How precisely the date is known.
+	Earliest  time.Time     // The first day the cover date could mean. Zero if the precision is unknown.
+	Latest    time.Time     // The last day the cover date could mean. Zero if the precision is unknown.
+}
+
+// Gets the original text of the cover date.
+func (d CoverDate) String() string {
+	return d.Text
+}
+
+// Parses a cover date, such as "October 2007", "April 1 2009", "Jan/Feb 1972", "January-February 1972",
+// "Summer 1985", "Mid December 1989" or "1990". The year always comes last. In a range of months the year
+// is for the first month, so "Dec/Jan 1971" is December 1971 to January 1972. Dates that can't be parsed
+// keep their text with an unknown precision.
+func ParseCoverDate(text string) CoverDate {
+	coverDate := CoverDate{Text: strings.TrimSpace(text)}
+	fields := strings.Fields(coverDate.Text)
+	if len(fields) == 0 {
+		return coverDate
+	}
+	year, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || len(fields[len(fields)-1]) != 4 {
+		return coverDate
+	}
+	fields = fields[:len(fields)-1]
+	if len(fields) == 2 {
+		if _, ok := coverDateSeasons[strings.ToLower(fields[0])]; ok {
+			// A qualified month, such as "Mid December", is still that month.
+			if _, ok := parseMonth(fields[1]); ok {
+				fields = fields[1:]
+			}
+		}
+	}
+
+	switch len(fields) {
+	case 0:
+		return coverDate.between(PrecisionYear, year, time.January, year, time.December)
+	case 1:
+		word := strings.ToLower(fields[0])
+		if word == "annual" {
+			return coverDate.between(PrecisionYear, year, time.January, year, time.December)
+		}
+		if season, ok := coverDateSeasons[word]; ok {
+			if season[1] < season[0] {
+				return coverDate.between(PrecisionSeason, year-1, season[0], year, season[1])
+			}
+			return coverDate.between(PrecisionSeason, year, season[0], year, season[1])
+		}
+		if month, ok := parseMonth(fields[0]); ok {
+			return coverDate.between(PrecisionMonth, year, month, year, month)
+		}
+		if months := strings.FieldsFunc(fields[0], isMonthSeparator); len(months) == 2 {
+			first, firstOk := parseMonth(months[0])
+			last, lastOk := parseMonth(months[1])
+			if firstOk && lastOk {
+				if last < first {
+					return coverDate.between(PrecisionDualMonth, year, first, year+1, last)
+				}
+				return coverDate.between(PrecisionDualMonth, year, first, year, last)
+			}
+		}
+	case 2:
+		month, ok := parseMonth(fields[0])
+		day, err := strconv.Atoi(strings.TrimSuffix(fields[1], ","))
+		if ok && err == nil {
+			date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+			// Reject days the month doesn't have, such as "February 30".
+			if date.Month() == month {
+				coverDate.Precision = PrecisionDay
+				coverDate.Earliest = date
+				coverDate.Latest = date
+			}
+		}
+	}
+	return coverDate
+}
+
+// Sets the range from the first day of the first month to the last day of the last month.
+func (d CoverDate) between(precision DatePrecision, firstYear int, first time.Month, lastYear int, last time.Month) CoverDate {
+	d.Precision = precision
+	d.Earliest = time.Date(firstYear, first, 1, 0, 0, 0, 0, time.UTC)
+	d.Latest = time.Date(lastYear, last+1, 0, 0, 0, 0, 0, time.UTC)
+	return d
+}
+
+// Parses a month's full name or its three-letter abbreviation, such as "September" or "Sep".
+func parseMonth(text string) (time.Month, bool) {
+	text = strings.ToLower(strings.TrimSuffix(text, "."))
+	if len(text) < 3 {
+		return 0, false
+	}
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		if text == name || text == name[:3] {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+func isMonthSeparator(r rune) bool {
+	return r == '/' || r == '-'
+}
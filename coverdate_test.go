@@ -0,0 +1,48 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseCoverDate(t *testing.T) {
+	tests := []struct {
+		text      string
+		precision DatePrecision
+		earliest  time.Time
+		latest    time.Time
+	}{
+		{"April 1 2009", PrecisionDay, date(2009, time.April, 1), date(2009, time.April, 1)},
+		{"October 2007", PrecisionMonth, date(2007, time.October, 1), date(2007, time.October, 31)},
+		{"Feb 2016", PrecisionMonth, date(2016, time.February, 1), date(2016, time.February, 29)},
+		{"Mid December 1989", PrecisionMonth, date(1989, time.December, 1), date(1989, time.December, 31)},
+		{"Jan/Feb 1972", PrecisionDualMonth, date(1972, time.January, 1), date(1972, time.February, 29)},
+		{"Jul/Sep 1971", PrecisionDualMonth, date(1971, time.July, 1), date(1971, time.September, 30)},
+		{"Dec/Jan 1971", PrecisionDualMonth, date(1971, time.December, 1), date(1972, time.January, 31)},
+		{"January-February 1972", PrecisionDualMonth, date(1972, time.January, 1), date(1972, time.February, 29)},
+		{"Summer 1985", PrecisionSeason, date(1985, time.June, 1), date(1985, time.August, 31)},
+		{"Winter 1985", PrecisionSeason, date(1984, time.December, 1), date(1985, time.February, 28)},
+		{"Holiday 1994", PrecisionSeason, date(1994, time.November, 1), date(1994, time.December, 31)},
+		{"Early  1978", PrecisionSeason, date(1978, time.January, 1), date(1978, time.April, 30)},
+		{"Mid 1990", PrecisionSeason, date(1990, time.May, 1), date(1990, time.August, 31)},
+		{"2014", PrecisionYear, date(2014, time.January, 1), date(2014, time.December, 31)},
+		{"Annual 1992", PrecisionYear, date(1992, time.January, 1), date(1992, time.December, 31)},
+		{"February 30 2001", PrecisionUnknown, time.Time{}, time.Time{}},
+		{"Someday 2001", PrecisionUnknown, time.Time{}, time.Time{}},
+		{"October", PrecisionUnknown, time.Time{}, time.Time{}},
+		{"", PrecisionUnknown, time.Time{}, time.Time{}},
+	}
+	for _, test := range tests {
+		coverDate := ParseCoverDate(test.text)
+		assert.Equal(t, test.precision, coverDate.Precision, test.text)
+		assert.Equal(t, test.earliest, coverDate.Earliest, test.text)
+		assert.Equal(t, test.latest, coverDate.Latest, test.text)
+	}
+	assert.Equal(t, "Summer 1985", ParseCoverDate(" Summer 1985 ").String())
+	assert.Equal(t, "dual-month", PrecisionDualMonth.String())
+}
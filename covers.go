@@ -0,0 +1,193 @@
+package externalissuesource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// What a cover image shows.
+type CoverKind int
+
+// Kinds of cover images.
+const (
+	CoverFront            CoverKind = iota // The full-size front cover.
+	CoverFrontThumbnail                    // The thumbnail of the front cover shown on the issue page.
+	CoverVariantThumbnail                  // The thumbnail of one of the issue's variant covers.
+)
+
+var coverKindNames = [...]string{"front", "front thumbnail", "variant thumbnail"}
+
+// Gets the name of the kind, such as `variant thumbnail`.
+func (k CoverKind) String() string {
+	if k < 0 || int(k) >= len(coverKindNames) {
+		return ""
+	}
+	return coverKindNames[k]
+}
+
+// An image of an issue's cover.
+type CoverImage struct {
+	Url    string
+	Kind   CoverKind
+	Width  int // The width in pixels if the source has it or the cover was downloaded, otherwise 0.
+	Height int // The height in pixels if the source has it or the cover was downloaded, otherwise 0.
+}
+
+// A cover that was downloaded to a local directory.
+type DownloadedCover struct {
+	Cover CoverImage // The cover with its width and height from the downloaded image.
+	Hash  string     // The hex-encoded SHA-256 hash of the image.
+	Path  string     // The path of the image in the directory, named by its hash.
+}
+
+// Downloads issue covers. `CbExternalSource` is a `CoverDownloader`, so check for it with a type assertion
+// on the `ExternalSource`.
+type CoverDownloader interface {
+	DownloadCovers(issue *Issue, dir string) ([]DownloadedCover, error)
+	DownloadCoversContext(ctx context.Context, issue *Issue, dir string) ([]DownloadedCover, error)
+}
+
+// Gets the covers from cb's cover box: the full-size front cover that the thumbnail links to,
+// the thumbnail and any thumbnails of the variant covers. Issues without a cover don't have any.
+// Any other `comic_graphics/` image in the box is taken as a variant thumbnail. None of the saved cb pages have
+// them, so that's an assumption about where cb shows them.
+func (p *CbParser) covers(doc *goquery.Document) []CoverImage {
+	covers := make([]CoverImage, 0)
+	coverBox := doc.Find("td[width=\"120\"]").First()
+	frontLink := coverBox.Find("a[href*=\"comic_graphics/\"]").First()
+	hrefValue, ex := frontLink.Attr("href")
+	if !ex {
+		return covers
+	}
+	covers = append(covers, CoverImage{Url: p.coverUrl(hrefValue), Kind: CoverFront})
+	coverBox.Find("img[src*=\"comic_graphics/\"]").Each(func(i int, s *goquery.Selection) {
+		srcValue := s.AttrOr("src", "")
+		if strings.HasSuffix(srcValue, "nocover.gif") {
+			return
+		}
+		cover := CoverImage{Url: p.coverUrl(srcValue), Kind: CoverVariantThumbnail}
+		if i == 0 && s.Parent().IsSelection(frontLink) {
+			cover.Kind = CoverFrontThumbnail
+		}
+		cover.Width, _ = strconv.Atoi(s.AttrOr("width", ""))
+		cover.Height, _ = strconv.Atoi(s.AttrOr("height", ""))
+		covers = append(covers, cover)
+	})
+	return covers
+}
+
+// Gets the absolute URL of an image on cb, which links to them relative to the site.
+func (p *CbParser) coverUrl(src string) string {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return src
+	}
+	return fmt.Sprintf("%s/%s", p.BaseUrl(), strings.TrimPrefix(src, "/"))
+}
+
+// Downloads the issue's covers into the directory. See `DownloadCoversContext`.
+func (s *CbExternalSource) DownloadCovers(issue *Issue, dir string) ([]DownloadedCover, error) {
+	return s.DownloadCoversContext(context.Background(), issue, dir)
+}
+
+// Downloads the issue's covers into the directory, which is created if it doesn't exist. Each image is stored
+// by the SHA-256 hash of its content, such as `dir/3f/3fa2...9c.jpg`, so the same image is only stored once
+// no matter how many issues or URLs it's downloaded for. The requests are canceled when the context is done.
+func (s *CbExternalSource) DownloadCoversContext(ctx context.Context, issue *Issue, dir string) ([]DownloadedCover, error) {
+	downloaded := make([]DownloadedCover, 0, len(issue.Covers))
+	for _, cover := range issue.Covers {
+		var content []byte
+		err := s.withRetry(ctx, func() error {
+			req, err := http.NewRequest(http.MethodGet, cover.Url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := s.do(req.WithContext(ctx))
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			content, err = ioutil.ReadAll(resp.Body)
+			return err
+		})
+		if err != nil {
+			return downloaded, err
+		}
+		downloadedCover, err := storeCover(dir, cover, content)
+		if err != nil {
+			return downloaded, err
+		}
+		downloaded = append(downloaded, *downloadedCover)
+	}
+	return downloaded, nil
+}
+
+// Gets the file extension for a cover from the format of its image, such as `.jpg` for `jpeg`. If the image
+// couldn't be decoded, it's taken from the path of the URL so a query string never ends up in the file name.
+func coverExtension(coverUrl string, format string) string {
+	switch format {
+	case "jpeg":
+		return ".jpg"
+	case "":
+	default:
+		return "." + format
+	}
+	u, err := url.Parse(coverUrl)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(path.Ext(u.Path))
+}
+
+// Stores the image in the directory by its hash unless an image with the same hash is already there.
+func storeCover(dir string, cover CoverImage, content []byte) (*DownloadedCover, error) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	format := ""
+	if config, decodedFormat, err := image.DecodeConfig(bytes.NewReader(content)); err == nil {
+		cover.Width = config.Width
+		cover.Height = config.Height
+		format = decodedFormat
+	}
+	coverPath := filepath.Join(dir, hash[:2], hash+coverExtension(cover.Url, format))
+	downloadedCover := &DownloadedCover{Cover: cover, Hash: hash, Path: coverPath}
+	if _, err := os.Stat(coverPath); err == nil {
+		return downloadedCover, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(coverPath), 0755); err != nil {
+		return nil, err
+	}
+	// Write to a temporary file first so a failed download never leaves a partial image under the hash.
+	tmp, err := ioutil.TempFile(filepath.Dir(coverPath), ".cover-")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(tmp, bytes.NewReader(content))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), coverPath)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return downloadedCover, nil
+}
@@ -0,0 +1,111 @@
+package externalissuesource
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCbParser_Issue_Covers(t *testing.T) {
+	parser := CbParser{}
+	file, err := os.Open("./testdata/cb_issue.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []CoverImage{
+		{Url: "http://comicbookdb.com/graphics/comic_graphics/1/208/103298_20070822161034_large.jpg", Kind: CoverFront},
+		{Url: "http://comicbookdb.com/graphics/comic_graphics/1/208/103298_20070822161034_thumb.jpg", Kind: CoverFrontThumbnail, Width: 100},
+	}, issue.Covers)
+
+	// A synthetic copy of cb_issue.html with variant thumbnails added to the cover box.
+	file2, err := os.Open("./testdata/cb_issue_covers.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file2)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Covers, 4)
+	assert.Equal(t, CoverImage{Url: "http://comicbookdb.com/graphics/comic_graphics/1/208/103306_20070822161345_thumb.jpg", Kind: CoverVariantThumbnail, Width: 48, Height: 74}, issue.Covers[3])
+
+	// Issues without a cover only have cb's placeholder image.
+	file3, err := os.Open("./testdata/cb_issue_nn.html")
+	defer file3.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file3)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Covers, 0)
+}
+
+func TestCbExternalSource_DownloadCovers(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.Nil(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 3, 5))))
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(buf.Bytes())
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "covers")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	// The same image at two URLs is only stored once, even with a query string.
+	issue := &Issue{Covers: []CoverImage{
+		{Url: fmt.Sprintf("%s/graphics/comic_graphics/1_large.png", ts.URL), Kind: CoverFront},
+		{Url: fmt.Sprintf("%s/graphics/comic_graphics/1_thumb.jpg?v=20070822", ts.URL), Kind: CoverFrontThumbnail, Width: 100},
+	}}
+	var downloader CoverDownloader = &externalSource
+	covers, err := downloader.DownloadCovers(issue, dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Len(t, covers, 2)
+	assert.Equal(t, covers[0].Hash, covers[1].Hash)
+	assert.Equal(t, covers[0].Path, covers[1].Path)
+	assert.Equal(t, filepath.Join(dir, covers[0].Hash[:2], covers[0].Hash+".png"), covers[0].Path)
+	assert.Equal(t, 3, covers[1].Cover.Width)
+	assert.Equal(t, 5, covers[1].Cover.Height)
+	content, err := ioutil.ReadFile(covers[0].Path)
+	assert.Nil(t, err)
+	assert.Equal(t, buf.Bytes(), content)
+	files, err := ioutil.ReadDir(filepath.Dir(covers[0].Path))
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestCbExternalSource_DownloadCovers_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "covers")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	issue := &Issue{Covers: []CoverImage{{Url: fmt.Sprintf("%s/graphics/comic_graphics/1_large.jpg", ts.URL)}}}
+	covers, err := externalSource.DownloadCovers(issue, dir)
+	assert.Len(t, covers, 0)
+	assert.IsType(t, &HTTPStatusError{}, err)
+}
+
+func TestCoverExtension(t *testing.T) {
+	assert.Equal(t, ".jpg", coverExtension("http://comicbookdb.com/graphics/1_large.jpeg", "jpeg"))
+	assert.Equal(t, ".png", coverExtension("http://comicbookdb.com/graphics/1_large.jpg?v=2", "png"))
+	assert.Equal(t, ".jpg", coverExtension("http://comicbookdb.com/graphics/1_large.JPG?v=2", ""))
+	assert.Equal(t, "", coverExtension("http://comicbookdb.com/graphics/1_large?file=1.jpg", ""))
+}
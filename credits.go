@@ -0,0 +1,102 @@
+package externalissuesource
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+// The creator's job on an issue or story.
+type CreditRole int
+
+// Roles for credits.
+const (
+	RoleUnknown     CreditRole = iota // The source's role isn't one of the known roles.
+	RoleWriter                        // Writes the story, such as "Writer(s)" on cb or "Script" on GCD.
+	RolePenciller                     // Pencils the art.
+	RoleInker                         // Inks the pencils.
+	RoleColorist                      // Colors the art.
+	RoleLetterer                      // Letters the dialogue and captions.
+	RoleEditor                        // Edits the issue or story.
+	RoleCoverArtist                   // Draws the cover.
+)
+
+var creditRoleNames = [...]string{"unknown", "writer", "penciller", "inker", "colorist", "letterer", "editor", "cover artist"}
+
+// The roles for each label the sources use, in lowercase without the `(s)` and colon, such as `writer` for "Writer(s):".
+var creditRoleLabels = map[string]CreditRole{
+	"writer":       RoleWriter,
+	"script":       RoleWriter,
+	"penciller":    RolePenciller,
+	"penciler":     RolePenciller,
+	"pencils":      RolePenciller,
+	"inker":        RoleInker,
+	"inks":         RoleInker,
+	"colorist":     RoleColorist,
+	"colors":       RoleColorist,
+	"letterer":     RoleLetterer,
+	"letters":      RoleLetterer,
+	"editor":       RoleEditor,
+	"editing":      RoleEditor,
+	"cover artist": RoleCoverArtist,
+	"cover":        RoleCoverArtist,
+}
+
+// The story index for the credits listed for the whole issue instead of one of its stories, such as the editors.
+const WholeIssue = -1
+
+// Gets the name of the role, such as `cover artist`.
+func (r CreditRole) String() string {
+	if r < 0 || int(r) >= len(creditRoleNames) {
+		return creditRoleNames[RoleUnknown]
+	}
+	return creditRoleNames[r]
+}
+
+// A creator credited on an issue.
+type Credit struct {
+	Role        CreditRole
+	CreatorName string // The name of the creator, such as `John Cassaday`.
+	CreatorId   string // unique identifier for the creator.
+	StoryIndex  int    // The index of the story in the issue the credit is for, starting at 0, or `WholeIssue`.
+}
+
+// Parses a role label, such as "Writer(s):", "Cover Artist(s):" or "Pencils". Unknown labels are `RoleUnknown`.
+func ParseCreditRole(label string) CreditRole {
+	label = strings.ToLower(strings.TrimSpace(label))
+	label = strings.TrimSuffix(label, ":")
+	label = strings.TrimSuffix(label, "(s)")
+	return creditRoleLabels[strings.TrimSpace(label)]
+}
+
+// Gets the credits from cb's creator lists in the section, such as
+// `<strong>Writer(s):</strong><br><a href="creator.php?ID=711">Joss Whedon</a>`.
+// Each story title (`span.size13`) moves on to the next story after storyIndex.
+func cbCredits(section *goquery.Selection, storyIndex int) []Credit {
+	credits := make([]Credit, 0)
+	role := RoleUnknown
+	section.Find("span.size13, strong, a").Each(func(i int, s *goquery.Selection) {
+		switch {
+		case s.Is("span.size13"):
+			storyIndex++
+			role = RoleUnknown
+		case goquery.NodeName(s) == "strong":
+			// Other labels, such as "Characters:", end the list of creators.
+			role = ParseCreditRole(s.Text())
+		case role != RoleUnknown:
+			hrefValue, ex := s.Attr("href")
+			if !ex || !strings.HasPrefix(hrefValue, "creator.php") {
+				return
+			}
+			credit := Credit{Role: role, CreatorName: strings.TrimSpace(s.Text()), StoryIndex: storyIndex}
+			// cb adds the creator's alias after the name, such as `Anthony Castrillo - 'MAC'`.
+			if aliasIndex := strings.Index(credit.CreatorName, " - '"); aliasIndex != -1 {
+				credit.CreatorName = credit.CreatorName[:aliasIndex]
+			}
+			if equalIndex := strings.Index(hrefValue, "="); equalIndex != -1 {
+				credit.CreatorId = hrefValue[equalIndex+1:]
+			}
+			credits = append(credits, credit)
+		}
+	})
+	return credits
+}
@@ -0,0 +1,29 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCreditRole(t *testing.T) {
+	for label, expected := range map[string]CreditRole{
+		"Writer(s):":       RoleWriter,
+		"Penciller(s):":    RolePenciller,
+		"Inker(s):":        RoleInker,
+		"Colorist(s):":     RoleColorist,
+		"Letterer(s):":     RoleLetterer,
+		"Editor(s):":       RoleEditor,
+		"Cover Artist(s):": RoleCoverArtist,
+		"Script":           RoleWriter,
+		" pencils ":        RolePenciller,
+		"Characters:":      RoleUnknown,
+		"":                 RoleUnknown,
+	} {
+		assert.Equal(t, expected, ParseCreditRole(label), label)
+	}
+}
+
+func TestCreditRole_String(t *testing.T) {
+	assert.Equal(t, "cover artist", RoleCoverArtist.String())
+	assert.Equal(t, "unknown", CreditRole(42).String())
+}
@@ -0,0 +1,45 @@
+package externalissuesource
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Returned when a source responds with a status code other than 200, such as a 404 when a
+// character was deleted or a 503 when the site is down.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("got bad status code from URL %s: %d", e.URL, e.StatusCode)
+}
+
+// Returned when a page can't be parsed. The cause is `ErrParse`, or `ErrConnection` when the
+// page is the source's connection error page, so it can be checked with `errors.Is`.
+type ParseError struct {
+	URL   string // The URL of the page. Only set when the page was fetched by a source.
+	Field string // What was being parsed, such as `document` for the whole page.
+	Cause error
+}
+
+func (e *ParseError) Error() string {
+	if e.URL == "" {
+		return fmt.Sprintf("%s (%s)", e.Cause, e.Field)
+	}
+	return fmt.Sprintf("%s at URL %s (%s)", e.Cause, e.URL, e.Field)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+// Adds the URL to a parse error from a parser, since the parsers only see the page's body.
+func withUrl(err error, url string) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && parseErr.URL == "" {
+		parseErr.URL = url
+	}
+	return err
+}
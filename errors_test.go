@@ -0,0 +1,68 @@
+package externalissuesource
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestCbExternalSource_ParseError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, err := os.Open("./testdata/cb_error.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	url := fmt.Sprintf("%s/issue.php?ID=22328", ts.URL)
+	_, err := externalSource.Issue(url)
+	var parseErr *ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, url, parseErr.URL)
+	assert.Equal(t, "document", parseErr.Field)
+	assert.True(t, errors.Is(err, ErrConnection))
+	assert.False(t, errors.Is(err, ErrParse))
+	assert.Equal(t, fmt.Sprintf("page returned connection issue at URL %s (document)", url), err.Error())
+}
+
+func TestCbExternalSource_HTTPStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	url := fmt.Sprintf("%s/character.php?ID=82321", ts.URL)
+	_, err := externalSource.CharacterPage(url)
+	var statusErr *HTTPStatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, url, statusErr.URL)
+}
+
+func TestRetryError_Unwrap(t *testing.T) {
+	err := &RetryError{Attempts: 3, Err: &HTTPStatusError{URL: "http://comicbookdb.com", StatusCode: http.StatusServiceUnavailable}}
+	var statusErr *HTTPStatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	assert.Equal(t, "giving up after 3 attempts: got bad status code from URL http://comicbookdb.com: 503", err.Error())
+}
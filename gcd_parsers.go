@@ -0,0 +1,444 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const gcdUrl = "https://www.comics.org"
+
+var (
+	regGcdSeriesYear = regexp.MustCompile(`(\d{4}) series\)`)
+	regGcdVariant    = regexp.MustCompile(`^(.*?)\s*\[(.+)\]$`)
+	regGcdStoryTitle = regexp.MustCompile(`^"([^"]*)"`)
+	regGcdYear       = regexp.MustCompile(`(\d{4})\s*$`)
+	regGcdMonths     = regexp.MustCompile(regMonths)
+	gcdOnSaleFormats = []string{"2006-01-02", "2006-01", "2006"}
+	// Checked in order against the binding and publishing format, so more specific formats go first.
+	gcdIssueFormats = []struct {
+		format Format
+		text   string
+	}{
+		{TPB, "trade paperback"},
+		{HC, "hardcover"},
+		{OGN, "graphic novel"},
+		{Prestige, "prestige"},
+		{Prestige, "squarebound"},
+		{Magazine, "magazine"},
+		{Manga, "manga"},
+		{DigitalMedia, "digital"},
+		{Ashcan, "ashcan"},
+		{Fanzine, "fanzine"},
+		{Standard, "saddle-stitched"},
+		{Standard, "comic book"},
+	}
+)
+
+// This struct implements parsing entities from the Grand Comics Database (comics.org) source.
+type GcdParser struct {
+	baseUrl string          // The base URL for constructing links. Default is https://www.comics.org if not provided.
+	onSale  OnSaleEstimator // Estimates the on-sale dates GCD doesn't have. Default is `DefaultOnSaleEstimator` if not provided.
+	logger  Logger          // Logs the warnings from `Issue`. Nothing is logged if not provided.
+}
+
+// Gets the base URL for constructing links for the parser.
+func (p *GcdParser) BaseUrl() string {
+	if p.baseUrl == "" {
+		p.baseUrl = gcdUrl
+	}
+	return p.baseUrl
+}
+
+// Parses an issue page and returns the corresponding struct.
+// Unlike the cb source, GCD records the actual on-sale date, so it's only derived from the cover
+// date when the issue doesn't have one. The warnings are logged with the parser's logger.
+func (p *GcdParser) Issue(body io.Reader) (*Issue, error) {
+	result, err := p.IssueResult(body)
+	if err != nil {
+		return nil, err
+	}
+	logWarnings(p.logger, result.Warnings)
+	return result.Issue, nil
+}
+
+// Parses an issue page and returns the corresponding struct with the warnings for the fields that couldn't be parsed.
+// `IsReprint` isn't supported: GCD notes reprints on each story rather than on the issue, so it's always false.
+func (p *GcdParser) IssueResult(body io.Reader) (*IssueResult, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	heading := doc.Find(".item_id h1").First()
+	if heading.Length() == 0 {
+		return nil, &ParseError{Field: "heading", Cause: ErrParse}
+	}
+	issue := new(Issue)
+
+	seriesLink := heading.Find(".issue_series a").First()
+	issue.Series = strings.TrimSpace(seriesLink.Text())
+	if hrefValue, ex := seriesLink.Attr("href"); ex {
+		issue.SeriesId = gcdId(hrefValue, "series")
+	}
+	if match := regGcdSeriesYear.FindStringSubmatch(heading.Text()); match != nil {
+		issue.Series = fmt.Sprintf("%s (%s)", issue.Series, match[1])
+	}
+	publisherLink := heading.Find("a[href^=\"/publisher/\"]").First()
+	issue.Vendor = strings.TrimSpace(publisherLink.Text())
+	if hrefValue, ex := publisherLink.Attr("href"); ex {
+		issue.VendorId = gcdId(hrefValue, "publisher")
+	}
+
+	numberText := strings.TrimSpace(heading.Find(".issue_number").Text())
+	if bracketIndex := strings.Index(numberText, "["); bracketIndex != -1 {
+		// The variant's name is in the brackets, such as `1 [Zeck Cover]` or `1 [Direct]`.
+		issue.VariantInfo = ParseVariantInfo(strings.Trim(numberText[bracketIndex:], "[]"))
+		numberText = strings.TrimSpace(numberText[:bracketIndex])
+	}
+	issue.Number = strings.TrimPrefix(numberText, "#")
+
+	doc.Find("#issue_links a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if issue.Id == "" && ex && strings.HasSuffix(hrefValue, "/history/") {
+			issue.Id = gcdId(hrefValue, "issue")
+		}
+	})
+
+	onSaleText := ""
+	formatText := ""
+	priceText, isbnText, barcodeText, ratingText := "", "", "", ""
+	pageCount := 0.0
+	issue.Versions = make([]IssueLink, 0)
+	doc.Find("#issue_data dl.pub_data dt").Each(func(i int, s *goquery.Selection) {
+		value := strings.TrimSpace(s.Next().Text())
+		switch strings.TrimSpace(s.Text()) {
+		case "On-sale Date:":
+			onSaleText = value
+		case "Binding:", "Publishing Format:":
+			formatText = fmt.Sprintf("%s %s", formatText, value)
+		case "Variant of:":
+			issue.IsVariant = true
+			s.Next().Find("a[href^=\"/issue/\"]").First().Each(func(i int, a *goquery.Selection) {
+				issue.VersionOf = p.issueLink(a)
+				issue.OriginalIssueId = issue.VersionOf.Id
+			})
+		case "Price:":
+			priceText = value
+		case "Pages:":
+			pageCount, _ = strconv.ParseFloat(value, 64)
+		case "ISBN:":
+			isbnText = value
+		case "Barcode:":
+			barcodeText = value
+		case "Rating:", "Publisher's Age Guidelines:":
+			ratingText = value
+		case "Variants:":
+			s.Next().Find("a[href^=\"/issue/\"]").Each(func(i int, a *goquery.Selection) {
+				issue.Versions = append(issue.Versions, *p.issueLink(a))
+			})
+		}
+	})
+	GcdProductDetails(issue, priceText, pageCount, isbnText, barcodeText, ratingText)
+	issue.Stories = make([]Story, 0)
+	// Each of the issue's contents has its type in a heading, such as "Cover" or "Story", before it.
+	doc.Find("#issue_contents div.story").Each(func(i int, s *goquery.Selection) {
+		if strings.TrimSpace(s.PrevFiltered("h3").Text()) == "Cover" {
+			return
+		}
+		text := strings.TrimSpace(s.Text())
+		story := Story{Credits: make([]Credit, 0), Characters: make([]CharacterLink, 0), PageCount: parsePageCount(text)}
+		if match := regGcdStoryTitle.FindStringSubmatch(text); match != nil {
+			story.Title = match[1]
+		}
+		issue.Stories = append(issue.Stories, story)
+	})
+	coverDateText := strings.TrimSpace(doc.Find(".item_id .issue_date").Text())
+	GcdIssueDates(issue, coverDateText, onSaleText, p.onSale)
+	issue.Format = GcdFormat(formatText)
+
+	warnings := make([]ParseWarning, 0)
+	if coverDateText != "" && issue.PublicationDate.IsZero() {
+		warnings = append(warnings, ParseWarning{Field: "cover date", Text: coverDateText, Reason: "unknown date format"})
+	}
+	if _, ok := parseGcdOnSaleDate(onSaleText); onSaleText != "" && !ok {
+		warnings = append(warnings, ParseWarning{Field: "on-sale date", Text: onSaleText, Reason: "unknown date format"})
+	}
+	if formatText = strings.TrimSpace(formatText); formatText != "" && issue.Format == Unknown {
+		warnings = append(warnings, ParseWarning{Field: "format", Text: formatText, Reason: "unknown format"})
+	}
+	return &IssueResult{Issue: issue, Warnings: warnings}, nil
+}
+
+// Parses a character's page and returns the corresponding struct.
+func (p *GcdParser) Character(body io.Reader) (*CharacterPage, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	heading := doc.Find(".item_id h1").First()
+	if heading.Length() == 0 {
+		return nil, &ParseError{Field: "heading", Cause: ErrParse}
+	}
+	characterPage := new(CharacterPage)
+	characterPage.Name = strings.TrimSpace(heading.Text())
+	characterPage.Publisher = strings.TrimSpace(doc.Find("#character_publisher").Text())
+	if characterPage.Publisher != "" {
+		characterPage.Title = fmt.Sprintf("%s (%s)", characterPage.Name, characterPage.Publisher)
+	} else {
+		characterPage.Title = characterPage.Name
+	}
+
+	otherIdentities := make([]CharacterLink, 0)
+	doc.Find("#character_relations li").Each(func(i int, s *goquery.Selection) {
+		link := s.Find("a[href^=\"/character/\"]").First()
+		hrefValue, ex := link.Attr("href")
+		if !ex {
+			return
+		}
+		name := strings.TrimSpace(link.Text())
+		relationType := strings.ToLower(s.Find(".relation_type").Text())
+		if characterPage.OtherName == "" && strings.HasPrefix(relationType, "civilian identity") {
+			characterPage.OtherName = name
+		}
+		otherIdentities = append(otherIdentities, CharacterLink{Url: fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue), Name: name})
+	})
+
+	issueLinks := make([]string, 0)
+	doc.Find("#character_appearances a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if ex && strings.HasPrefix(hrefValue, "/issue/") {
+			issueLinks = append(issueLinks, fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue))
+		}
+	})
+
+	characterPage.IssueLinks = issueLinks
+	characterPage.OtherIdentities = otherIdentities
+	return characterPage, nil
+}
+
+// Parses the links to character profiles and their names from the search page,
+// with each character's ID, publisher and disambiguation.
+func (p *GcdParser) CharacterSearch(body io.Reader) (*CharacterSearchResult, error) {
+	searchResult, err := p.Search(body, SearchCharacter)
+	if err != nil {
+		return nil, err
+	}
+	return &CharacterSearchResult{Results: searchResult.Characters}, nil
+}
+
+// Parses a series page and returns the corresponding struct.
+// GCD shows a variant's name in brackets after its number, such as `22 [Wolverine Cover]`.
+func (p *GcdParser) Series(body io.Reader) (*SeriesPage, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	heading := doc.Find(".item_id h1").First()
+	if heading.Length() == 0 {
+		return nil, &ParseError{Field: "heading", Cause: ErrParse}
+	}
+	seriesPage := new(SeriesPage)
+	seriesPage.Name = strings.TrimSpace(heading.Find(".series_name").Text())
+	if match := regGcdSeriesYear.FindStringSubmatch(heading.Text()); match != nil {
+		seriesPage.StartYear, _ = strconv.Atoi(match[1])
+	}
+	seriesPage.Publisher = strings.TrimSpace(heading.Find("a[href^=\"/publisher/\"]").First().Text())
+
+	formatText := ""
+	doc.Find("#series_data dl.pub_data dt").Each(func(i int, s *goquery.Selection) {
+		switch strings.TrimSpace(s.Text()) {
+		case "Binding:", "Publishing Format:":
+			formatText = fmt.Sprintf("%s %s", formatText, strings.TrimSpace(s.Next().Text()))
+		}
+	})
+	seriesPage.Format = GcdFormat(formatText)
+
+	doc.Find("#series_links a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if seriesPage.Id == "" && ex && strings.HasSuffix(hrefValue, "/history/") {
+			seriesPage.Id = gcdId(hrefValue, "series")
+		}
+	})
+
+	issueLinks := make([]SeriesIssueLink, 0)
+	doc.Find("#issue_list a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if !ex || !strings.HasPrefix(hrefValue, "/issue/") {
+			return
+		}
+		issueLink := SeriesIssueLink{Url: fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue), Number: strings.TrimSpace(s.Text())}
+		if match := regGcdVariant.FindStringSubmatch(issueLink.Number); match != nil {
+			issueLink.Number = match[1]
+			issueLink.VariantName = match[2]
+			issueLink.IsVariant = true
+		}
+		issueLinks = append(issueLinks, issueLink)
+	})
+	seriesPage.IssueLinks = issueLinks
+	return seriesPage, nil
+}
+
+// Parses a publisher's page and returns the corresponding struct.
+func (p *GcdParser) Publisher(body io.Reader) (*Publisher, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	heading := doc.Find(".item_id h1").First()
+	if heading.Length() == 0 {
+		return nil, &ParseError{Field: "heading", Cause: ErrParse}
+	}
+	publisher := new(Publisher)
+	publisher.Name = strings.TrimSpace(heading.Find(".publisher_name").Text())
+
+	doc.Find("#publisher_data dl.pub_data dt").Each(func(i int, s *goquery.Selection) {
+		switch strings.TrimSpace(s.Text()) {
+		case "Years of Operation:":
+			// The last year is blank for the publishers that are still active, such as `1939 - `.
+			if match := regPublisherYears.FindStringSubmatch(s.Next().Text()); match != nil {
+				publisher.YearBegan, _ = strconv.Atoi(match[1])
+				publisher.YearEnded, _ = strconv.Atoi(match[2])
+			}
+		case "Imprint of:":
+			parent := s.Next().Find("a[href^=\"/publisher/\"]").First()
+			if hrefValue, ex := parent.Attr("href"); ex {
+				publisher.Parent = &PublisherLink{Url: fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue), Name: strings.TrimSpace(parent.Text())}
+			}
+		}
+	})
+
+	doc.Find("#publisher_links a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if publisher.Id == "" && ex && strings.HasSuffix(hrefValue, "/history/") {
+			publisher.Id = gcdId(hrefValue, "publisher")
+		}
+	})
+
+	imprints := make([]PublisherLink, 0)
+	doc.Find("#publisher_imprints a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if ex && strings.HasPrefix(hrefValue, "/publisher/") {
+			imprints = append(imprints, PublisherLink{Url: fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue), Name: strings.TrimSpace(s.Text())})
+		}
+	})
+	publisher.Imprints = imprints
+
+	seriesLinks := make([]SeriesLink, 0)
+	doc.Find("#publisher_series tr").Each(func(i int, s *goquery.Selection) {
+		link := s.Find("a[href^=\"/series/\"]").First()
+		hrefValue, ex := link.Attr("href")
+		if !ex {
+			return
+		}
+		seriesLink := SeriesLink{Url: fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue), Name: strings.TrimSpace(link.Text())}
+		seriesLink.StartYear, _ = strconv.Atoi(strings.TrimSpace(s.Find("td").Eq(1).Text()))
+		seriesLinks = append(seriesLinks, seriesLink)
+	})
+	publisher.SeriesLinks = seriesLinks
+	return publisher, nil
+}
+
+// GCD doesn't have pages for story arcs, so this always returns `ErrUnsupported`.
+func (p *GcdParser) StoryArc(body io.Reader) (*StoryArc, error) {
+	return nil, ErrUnsupported
+}
+
+// Sets the issue's cover price, page count, ISBN, UPC and rating from GCD's fields, such as `2.99 USD; 3.75 CAD`
+// for the price. GCD's barcode is the UPC unless it's an ISBN-13.
+func GcdProductDetails(issue *Issue, price string, pageCount float64, isbn string, barcode string, rating string) {
+	if price != "" {
+		issue.CoverPrice = parsePrice(price)
+	}
+	issue.PageCount = int(pageCount)
+	issue.ISBN = normalizeIsbn(isbn)
+	if barcode != "" {
+		setBarcode(issue, barcode)
+	}
+	issue.Rating = ParseRating(rating)
+}
+
+// Sets the issue's cover date, publication date and on-sale date from GCD's cover date and on-sale date.
+// GCD records the actual on-sale date, so it's only estimated from the cover date with the estimator,
+// or `DefaultOnSaleEstimator` if it's nil, when the issue doesn't have one.
+func GcdIssueDates(issue *Issue, coverDate string, onSaleDate string, estimator OnSaleEstimator) {
+	issue.CoverDate = ParseCoverDate(coverDate)
+	issue.PublicationDate, issue.MonthUncertain = parseGcdCoverDate(coverDate)
+	if onSale, ok := parseGcdOnSaleDate(onSaleDate); ok {
+		issue.OnSaleDate = onSale
+		return
+	}
+	estimateOnSale(estimator, issue)
+}
+
+// Parses a GCD cover date, such as "October 2007", "Summer 2001" or "January-February 1972".
+// The last month mentioned is used as the publication month. If there's no month, it returns
+// January of the year and that the month is uncertain.
+func parseGcdCoverDate(text string) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	yearMatch := regGcdYear.FindStringSubmatch(text)
+	if yearMatch == nil {
+		return time.Time{}, false
+	}
+	months := regGcdMonths.FindAllString(text, -1)
+	if len(months) == 0 {
+		pubDate, _ := time.Parse("2006", yearMatch[1])
+		return pubDate, true
+	}
+	pubDate, err := time.Parse("January 2006", fmt.Sprintf("%s %s", months[len(months)-1], yearMatch[1]))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return pubDate, false
+}
+
+// Parses a GCD on-sale date, such as "2007-08-22", "2001-06" or "2001". GCD's database dump pads
+// unknown parts with zeros, such as "2001-06-00", so those are accepted as well.
+// Returns whether the date could be parsed.
+func parseGcdOnSaleDate(text string) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	for strings.HasSuffix(text, "-00") {
+		text = strings.TrimSuffix(text, "-00")
+	}
+	for _, format := range gcdOnSaleFormats {
+		if onSaleDate, err := time.Parse(format, text); err == nil {
+			return onSaleDate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Maps GCD's binding or publishing format text, such as "saddle-stitched" or "Trade Paperback",
+// to a format. Returns `Unknown` if none of the formats match.
+func GcdFormat(text string) Format {
+	text = strings.ToLower(text)
+	for _, f := range gcdIssueFormats {
+		if strings.Contains(text, f.text) {
+			return f.format
+		}
+	}
+	return Unknown
+}
+
+// Gets the link to the issue from the anchor, such as `<a href="/issue/37286/">Marvel Super Heroes Secret Wars #1 [Direct]</a>`.
+func (p *GcdParser) issueLink(a *goquery.Selection) *IssueLink {
+	hrefValue := a.AttrOr("href", "")
+	return &IssueLink{Url: fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue), Id: gcdId(hrefValue, "issue"), Title: strings.TrimSpace(a.Text())}
+}
+
+// Gets the ID from a GCD link, such as `/issue/293849/history/` for the `issue` kind.
+func gcdId(href string, kind string) string {
+	parts := strings.Split(strings.Trim(href, "/"), "/")
+	if len(parts) >= 2 && parts[0] == kind {
+		return parts[1]
+	}
+	return ""
+}
+
+func NewGcdParser(baseUrl string) ExternalSourceParser {
+	gcdParser := GcdParser{baseUrl: baseUrl}
+	return &gcdParser
+}
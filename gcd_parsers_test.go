@@ -0,0 +1,192 @@
+package externalissuesource
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+// The GCD pages in testdata are synthetic, modeled on comics.org's markup. Each one describes what it models.
+func TestGcdParser_Issue(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/issue.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "293849", issue.Id)
+	assert.Equal(t, "22", issue.Number)
+	assert.Equal(t, "11105", issue.SeriesId)
+	// GCD doesn't note reprints on the issue.
+	assert.False(t, issue.IsReprint)
+	assert.Equal(t, "Astonishing X-Men (2004)", issue.Series)
+	assert.Equal(t, "Marvel", issue.Vendor)
+	assert.Equal(t, "78", issue.VendorId)
+	assert.Equal(t, 2007, issue.PublicationDate.Year())
+	assert.Equal(t, time.October, issue.PublicationDate.Month())
+	assert.Equal(t, 2007, issue.OnSaleDate.Year())
+	assert.Equal(t, time.August, issue.OnSaleDate.Month())
+	assert.Equal(t, 22, issue.OnSaleDate.Day())
+	assert.False(t, issue.OnSaleEstimated)
+	assert.False(t, issue.MonthUncertain)
+	assert.False(t, issue.IsVariant)
+	assert.Equal(t, Standard, issue.Format)
+	assert.Nil(t, issue.VersionOf)
+	assert.Equal(t, &Price{Amount: 299, Currency: "USD"}, issue.CoverPrice)
+	assert.Equal(t, 32, issue.PageCount)
+	assert.Equal(t, "75960605514102211", issue.UPC)
+	assert.Equal(t, "", issue.ISBN)
+	assert.Equal(t, []IssueLink{{Url: "https://www.comics.org/issue/293850/", Id: "293850", Title: "Astonishing X-Men #22 [Wolverine Cover]"}}, issue.Versions)
+	// The cover isn't a story.
+	assert.Len(t, issue.Stories, 1)
+	assert.Equal(t, "Unstoppable", issue.Stories[0].Title)
+	assert.Equal(t, 22, issue.Stories[0].PageCount)
+}
+
+func TestGcdParser_Issue_Variant(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/issue_variant.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "37287", issue.Id)
+	assert.Equal(t, "1", issue.Number)
+	assert.True(t, issue.IsVariant)
+	assert.Equal(t, "37286", issue.OriginalIssueId)
+	assert.Equal(t, "Marvel Super Heroes Secret Wars #1 [Direct]", issue.VersionOf.Title)
+	assert.Equal(t, VariantInfo{}, issue.VariantInfo)
+	assert.Equal(t, time.May, issue.PublicationDate.Month())
+	assert.Equal(t, 1984, issue.OnSaleDate.Year())
+	assert.Equal(t, time.February, issue.OnSaleDate.Month())
+	assert.Equal(t, 14, issue.OnSaleDate.Day())
+}
+
+func TestGcdParser_Issue_Tpb(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/issue_tpb.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "", issue.Number)
+	assert.Equal(t, TPB, issue.Format)
+	assert.True(t, issue.MonthUncertain)
+	assert.Equal(t, "0785108223", issue.ISBN)
+	assert.Equal(t, 304, issue.PageCount)
+	assert.Equal(t, 2001, issue.PublicationDate.Year())
+	assert.Equal(t, 2001, issue.OnSaleDate.Year())
+	assert.Equal(t, time.June, issue.OnSaleDate.Month())
+	assert.False(t, issue.OnSaleEstimated)
+	assert.Equal(t, "Summer 2001", issue.CoverDate.Text)
+	assert.Equal(t, PrecisionSeason, issue.CoverDate.Precision)
+	assert.Equal(t, time.Date(2001, time.June, 1, 0, 0, 0, 0, time.UTC), issue.CoverDate.Earliest)
+	assert.Equal(t, time.Date(2001, time.August, 31, 0, 0, 0, 0, time.UTC), issue.CoverDate.Latest)
+}
+
+func TestGcdParser_Character(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/character.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	c, err := parser.Character(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "Cyclops", c.Name)
+	assert.Equal(t, "Marvel", c.Publisher)
+	assert.Equal(t, "Cyclops (Marvel)", c.Title)
+	assert.Equal(t, "Scott Summers", c.OtherName)
+	assert.Len(t, c.IssueLinks, 3)
+	assert.Equal(t, "https://www.comics.org/issue/1950/", c.IssueLinks[0])
+	assert.Len(t, c.OtherIdentities, 2)
+	assert.Equal(t, "Slym Dayspring", c.OtherIdentities[1].Name)
+	assert.Equal(t, "https://www.comics.org/character/40519/", c.OtherIdentities[1].Url)
+}
+
+func TestGcdParser_CharacterSearch(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/search.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	c, err := parser.CharacterSearch(file)
+	assert.Nil(t, err)
+	assert.Len(t, c.Results, 3)
+	assert.Equal(t, "Cyclops", c.Results[0].Name)
+	assert.Equal(t, "1", c.Results[0].Id)
+	assert.Equal(t, "Marvel", c.Results[0].Publisher)
+	assert.Equal(t, CharacterLink{
+		Url:            "https://www.comics.org/character/18421/",
+		Name:           "Cyclops [Greek mythology]",
+		Id:             "18421",
+		Publisher:      "DC",
+		Disambiguation: "Greek mythology",
+	}, c.Results[1])
+	assert.Equal(t, "https://www.comics.org/character/1/", c.Results[0].Url)
+}
+
+func TestGcdParser_Error(t *testing.T) {
+	file, err := os.Open("./testdata/cb_error.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, issue)
+	assert.True(t, errors.Is(err, ErrParse))
+
+	file.Seek(0, 0)
+	character, err := parser.Character(file)
+	assert.Nil(t, character)
+	assert.True(t, errors.Is(err, ErrParse))
+}
+
+func TestGcdParser_Series(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/series.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	series, err := parser.Series(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "11105", series.Id)
+	assert.Equal(t, "Astonishing X-Men", series.Name)
+	assert.Equal(t, 2004, series.StartYear)
+	assert.Equal(t, "Marvel", series.Publisher)
+	assert.Equal(t, "", series.Volume)
+	assert.Equal(t, Standard, series.Format)
+	assert.Len(t, series.IssueLinks, 6)
+	assert.Equal(t, SeriesIssueLink{Url: "https://www.comics.org/issue/106537/", Number: "1"}, series.IssueLinks[0])
+	assert.Equal(t, SeriesIssueLink{Url: "https://www.comics.org/issue/106538/", Number: "1", IsVariant: true, VariantName: "Dell'Otto Cover"}, series.IssueLinks[1])
+	assert.Equal(t, "Wolverine Cover", series.IssueLinks[5].VariantName)
+}
+
+func TestGcdParser_Publisher(t *testing.T) {
+	file, err := os.Open("./testdata/gcd/publisher.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := GcdParser{}
+	publisher, err := parser.Publisher(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "78", publisher.Id)
+	assert.Equal(t, "Marvel", publisher.Name)
+	assert.Nil(t, publisher.Parent)
+	assert.Equal(t, 1939, publisher.YearBegan)
+	assert.Equal(t, 0, publisher.YearEnded)
+	assert.Equal(t, []PublisherLink{
+		{Url: "https://www.comics.org/publisher/2310/", Name: "Epic"},
+		{Url: "https://www.comics.org/publisher/3872/", Name: "Icon"},
+	}, publisher.Imprints)
+	assert.Len(t, publisher.SeriesLinks, 3)
+	assert.Equal(t, SeriesLink{Url: "https://www.comics.org/series/11105/", Name: "Astonishing X-Men", StartYear: 2004}, publisher.SeriesLinks[0])
+
+	file2, err := os.Open("./testdata/gcd/publisher_imprint.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	imprint, err := parser.Publisher(file2)
+	assert.Nil(t, err)
+	assert.Equal(t, "2310", imprint.Id)
+	assert.Equal(t, &PublisherLink{Url: "https://www.comics.org/publisher/78/", Name: "Marvel"}, imprint.Parent)
+	assert.Equal(t, 1982, imprint.YearBegan)
+	assert.Equal(t, 1996, imprint.YearEnded)
+	assert.Empty(t, imprint.Imprints)
+	assert.Equal(t, []SeriesLink{{Url: "https://www.comics.org/series/3460/", Name: "Elektra: Assassin", StartYear: 1986}}, imprint.SeriesLinks)
+}
@@ -0,0 +1,183 @@
+package externalissuesource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const gcdSearchPath = "/searchNew/"
+
+// Configuration options for the GCD source.
+type GcdExternalSourceConfig struct {
+	IssueWorkers    int             // The number of issues to fetch concurrently for a character. Default is 5 if not provided.
+	OnSaleEstimator OnSaleEstimator // Estimates the on-sale dates GCD doesn't have. Default is `DefaultOnSaleEstimator` if not provided.
+	Logger          Logger          // Logs the warnings from parsing issues. Nothing is logged if not provided.
+}
+
+// Gets the number of workers for fetching a character's issues.
+func (c *GcdExternalSourceConfig) issueWorkers() int {
+	if c.IssueWorkers <= 0 {
+		return defaultIssueWorkers
+	}
+	return c.IssueWorkers
+}
+
+// Fetches entities from the Grand Comics Database (comics.org).
+type GcdExternalSource struct {
+	httpClient *http.Client
+	parser     ExternalSourceParser
+	config     *GcdExternalSourceConfig
+}
+
+// Fetches an issue from the issue page.
+func (s *GcdExternalSource) Issue(url string) (*Issue, error) {
+	return s.IssueContext(context.Background(), url)
+}
+
+// Fetches an issue from the issue page. The request is canceled when the context is done.
+func (s *GcdExternalSource) IssueContext(ctx context.Context, url string) (*Issue, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return parseIssue(s.parser, resp.Body, url, s.config.Logger)
+}
+
+// Fetches the character page.
+func (s *GcdExternalSource) CharacterPage(url string) (*CharacterPage, error) {
+	return s.CharacterPageContext(context.Background(), url)
+}
+
+// Fetches the character page. The request is canceled when the context is done.
+func (s *GcdExternalSource) CharacterPageContext(ctx context.Context, url string) (*CharacterPage, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	characterPage, err := s.parser.Character(resp.Body)
+	return characterPage, withUrl(err, url)
+}
+
+// Fetches the character page and then concurrently fetches each of the character's issues.
+// See `CbExternalSource.Character` for how failed issues are reported.
+func (s *GcdExternalSource) Character(url string) (*Character, error) {
+	return s.CharacterContext(context.Background(), url)
+}
+
+// Same as `Character`, but stops fetching the remaining issues as soon as the context is done
+// and returns the context's error.
+func (s *GcdExternalSource) CharacterContext(ctx context.Context, url string) (*Character, error) {
+	return fetchCharacter(ctx, s, url, s.config.issueWorkers())
+}
+
+// Fetches the series page with the links to all of its issues.
+func (s *GcdExternalSource) Series(url string) (*SeriesPage, error) {
+	return s.SeriesContext(context.Background(), url)
+}
+
+// Fetches the series page. The request is canceled when the context is done.
+func (s *GcdExternalSource) SeriesContext(ctx context.Context, url string) (*SeriesPage, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	seriesPage, err := s.parser.Series(resp.Body)
+	return seriesPage, withUrl(err, url)
+}
+
+// Fetches the publisher page with its imprints and the links to its series.
+func (s *GcdExternalSource) Publisher(url string) (*Publisher, error) {
+	return s.PublisherContext(context.Background(), url)
+}
+
+// Fetches the publisher page. The request is canceled when the context is done.
+func (s *GcdExternalSource) PublisherContext(ctx context.Context, url string) (*Publisher, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	publisher, err := s.parser.Publisher(resp.Body)
+	return publisher, withUrl(err, url)
+}
+
+// GCD doesn't have pages for story arcs, so this always returns `ErrUnsupported`.
+func (s *GcdExternalSource) StoryArc(url string) (*StoryArc, error) {
+	return s.StoryArcContext(context.Background(), url)
+}
+
+// GCD doesn't have pages for story arcs, so this always returns `ErrUnsupported` without sending a request.
+func (s *GcdExternalSource) StoryArcContext(ctx context.Context, url string) (*StoryArc, error) {
+	return nil, ErrUnsupported
+}
+
+// Performs a search on the provided query and returns the search result for found characters.
+func (s *GcdExternalSource) SearchCharacter(query string) (CharacterSearchResult, error) {
+	return s.SearchCharacterContext(context.Background(), query)
+}
+
+// Performs a search on the provided query and returns the search result for found characters.
+// The request is canceled when the context is done.
+func (s *GcdExternalSource) SearchCharacterContext(ctx context.Context, query string) (CharacterSearchResult, error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", s.parser.BaseUrl(), gcdSearchPath), nil)
+	if err != nil {
+		return CharacterSearchResult{}, err
+	}
+	q := request.URL.Query()
+	q.Add("q", strings.TrimSpace(query))
+	q.Add("search_object", "character")
+	request.URL.RawQuery = q.Encode()
+	response, err := s.do(ctx, request)
+	if err != nil {
+		return CharacterSearchResult{}, err
+	}
+	defer response.Body.Close()
+	characterSearchResult, err := s.parser.CharacterSearch(response.Body)
+	if err != nil {
+		return CharacterSearchResult{}, withUrl(err, request.URL.String())
+	}
+	return *characterSearchResult, nil
+}
+
+// Sends the request with the context and checks the response status.
+// The caller is responsible for closing the body when there's no error.
+func (s *GcdExternalSource) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	resp, err := s.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &HTTPStatusError{URL: req.URL.String(), StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+func NewGcdExternalSource(httpClient *http.Client, config *GcdExternalSourceConfig) ExternalSource {
+	return &GcdExternalSource{
+		httpClient: httpClient,
+		parser:     &GcdParser{onSale: config.OnSaleEstimator},
+		config:     config,
+	}
+}
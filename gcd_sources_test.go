@@ -0,0 +1,116 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGcdExternalSource_SearchCharacter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/searchNew/", r.URL.Path)
+		assert.Equal(t, "cyclops", r.URL.Query().Get("q"))
+		assert.Equal(t, "character", r.URL.Query().Get("search_object"))
+		file, err := os.Open("./testdata/gcd/search.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := GcdExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewGcdParser(ts.URL),
+		config:     &GcdExternalSourceConfig{},
+	}
+	searchResult, err := externalSource.SearchCharacter(" cyclops ")
+	assert.Nil(t, err)
+	assert.Len(t, searchResult.Results, 3)
+	for _, result := range searchResult.Results {
+		assert.True(t, strings.HasPrefix(result.Url, ts.URL))
+	}
+}
+
+func TestGcdExternalSource_Character(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixtures := map[string]string{
+			"/character/1/":  "./testdata/gcd/character.html",
+			"/issue/293849/": "./testdata/gcd/issue.html",
+			"/issue/37287/":  "./testdata/gcd/issue_variant.html",
+		}
+		path, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := GcdExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewGcdParser(ts.URL),
+		config:     &GcdExternalSourceConfig{},
+	}
+	character, err := externalSource.Character(fmt.Sprintf("%s/character/1/", ts.URL))
+	linkErrors, ok := err.(IssueLinkErrors)
+	assert.True(t, ok)
+	assert.Len(t, linkErrors, 1)
+	assert.Contains(t, linkErrors, fmt.Sprintf("%s/issue/1950/", ts.URL))
+	assert.Equal(t, "Cyclops", character.Name)
+	assert.Len(t, character.OtherIdentities, 2)
+	assert.Len(t, character.Issues, 2)
+	assert.Equal(t, "37287", character.Issues[0].Id)
+	assert.Equal(t, "293849", character.Issues[1].Id)
+}
+
+func TestGcdExternalSource_Series(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/series/11105/", r.URL.Path)
+		file, err := os.Open("./testdata/gcd/series.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := GcdExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewGcdParser(ts.URL),
+		config:     &GcdExternalSourceConfig{},
+	}
+	series, err := externalSource.Series(fmt.Sprintf("%s/series/11105/", ts.URL))
+	assert.Nil(t, err)
+	assert.Equal(t, "Astonishing X-Men", series.Name)
+	assert.Len(t, series.IssueLinks, 6)
+	assert.Equal(t, fmt.Sprintf("%s/issue/293849/", ts.URL), series.IssueLinks[4].Url)
+}
+
+func TestGcdExternalSource_StoryArc(t *testing.T) {
+	externalSource := NewGcdExternalSource(http.DefaultClient, &GcdExternalSourceConfig{})
+	_, err := externalSource.StoryArc("https://www.comics.org/story_arc/1/")
+	assert.Equal(t, ErrUnsupported, err)
+}
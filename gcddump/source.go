@@ -0,0 +1,673 @@
+// Package gcddump reads entities from a local copy of the Grand Comics Database (comics.org) SQLite dump,
+// so backfills can run offline with the same structs as the HTML scrapers.
+package gcddump
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/aimeelaplant/externalissuesource"
+	_ "github.com/mattn/go-sqlite3"
+	"net/url"
+	"strings"
+)
+
+// The base URL for the links to GCD entities. The links are the same as the ones from `GcdParser`,
+// so they can be used with either source.
+const baseUrl = "https://www.comics.org"
+
+var (
+	ErrInvalidUrl = errors.New("url is not a link to a GCD entity")
+	ErrNotFound   = errors.New("entity not found in the GCD dump")
+)
+
+const (
+	issueQuery = `SELECT i.id, i.number, i.publication_date, i.on_sale_date, i.variant_of_id IS NOT NULL, i.variant_name,
+		i.price, COALESCE(i.page_count, 0), i.isbn, i.barcode, i.rating,
+		s.id, s.name, s.year_began, s.publishing_format, s.binding, p.id, p.name
+	FROM gcd_issue i
+	JOIN gcd_series s ON s.id = i.series_id
+	JOIN gcd_publisher p ON p.id = s.publisher_id
+	WHERE i.id = ? AND i.deleted = 0`
+	// Counts the issue's comic stories and how many of them are reprinted from another story.
+	issueReprintQuery = `SELECT COUNT(*), COUNT(r.id)
+	FROM gcd_story st
+	JOIN gcd_story_type t ON t.id = st.type_id
+	LEFT JOIN (SELECT DISTINCT target_id AS id FROM gcd_reprint) r ON r.id = st.id
+	WHERE st.issue_id = ? AND st.deleted = 0 AND t.name = 'comic story'`
+	// The issue's comic stories in the order they're printed, with the issue the story is reprinted from.
+	// The origin issue is recorded on the reprint or only on the origin story.
+	storiesQuery = `SELECT st.id, st.title, COALESCE(st.page_count, 0), oi.id, os.name, oi.number, oi.variant_name
+	FROM gcd_story st
+	JOIN gcd_story_type t ON t.id = st.type_id
+	LEFT JOIN (SELECT target_id, MIN(id) AS id FROM gcd_reprint GROUP BY target_id) fr ON fr.target_id = st.id
+	LEFT JOIN gcd_reprint r ON r.id = fr.id
+	LEFT JOIN gcd_story o ON o.id = r.origin_id
+	LEFT JOIN gcd_issue oi ON oi.id = COALESCE(r.origin_issue_id, o.issue_id) AND oi.deleted = 0
+	LEFT JOIN gcd_series os ON os.id = oi.series_id
+	WHERE st.issue_id = ? AND st.deleted = 0 AND t.name = 'comic story'
+	ORDER BY st.sequence_number, st.id`
+	storyCharactersQuery = `SELECT c.id, c.name
+	FROM gcd_story_character sc
+	JOIN gcd_character c ON c.id = sc.character_id AND c.deleted = 0
+	WHERE sc.story_id = ? AND sc.deleted = 0
+	ORDER BY sc.id`
+	// The issue the issue is a variant of, and the variants of the issue.
+	versionOfQuery = `SELECT o.id, s.name, o.number, o.variant_name
+	FROM gcd_issue i
+	JOIN gcd_issue o ON o.id = i.variant_of_id AND o.deleted = 0
+	JOIN gcd_series s ON s.id = o.series_id
+	WHERE i.id = ?`
+	versionsQuery = `SELECT v.id, s.name, v.number, v.variant_name
+	FROM gcd_issue v
+	JOIN gcd_series s ON s.id = v.series_id
+	WHERE v.variant_of_id = ? AND v.deleted = 0
+	ORDER BY v.sort_code, v.id`
+	seriesQuery = `SELECT s.name, s.year_began, s.publishing_format, s.binding, p.name
+	FROM gcd_series s
+	JOIN gcd_publisher p ON p.id = s.publisher_id
+	WHERE s.id = ? AND s.deleted = 0`
+	// The series' issues in the order GCD lists them, which puts the variants after their issue.
+	seriesIssuesQuery = `SELECT id, number, variant_of_id IS NOT NULL, variant_name
+	FROM gcd_issue
+	WHERE series_id = ? AND deleted = 0
+	ORDER BY sort_code, id`
+	// The publisher with the publisher it's an imprint of, if any.
+	publisherQuery = `SELECT p.name, COALESCE(p.year_began, 0), COALESCE(p.year_ended, 0), pp.id, pp.name
+	FROM gcd_publisher p
+	LEFT JOIN gcd_publisher pp ON pp.id = p.parent_id AND pp.deleted = 0
+	WHERE p.id = ? AND p.deleted = 0`
+	imprintsQuery = `SELECT id, name FROM gcd_publisher
+	WHERE parent_id = ? AND deleted = 0
+	ORDER BY name, id`
+	publisherSeriesQuery = `SELECT id, name, year_began FROM gcd_series
+	WHERE publisher_id = ? AND deleted = 0
+	ORDER BY name, year_began, id`
+	characterQuery = `SELECT name FROM gcd_character WHERE id = ? AND deleted = 0`
+	// The character's appearances in the order they were published, with the publisher of each issue.
+	appearancesQuery = `SELECT DISTINCT i.id, i.key_date, p.name
+	FROM gcd_story_character sc
+	JOIN gcd_story st ON st.id = sc.story_id AND st.deleted = 0
+	JOIN gcd_issue i ON i.id = st.issue_id AND i.deleted = 0
+	JOIN gcd_series s ON s.id = i.series_id
+	JOIN gcd_publisher p ON p.id = s.publisher_id
+	WHERE sc.character_id = ? AND sc.deleted = 0
+	ORDER BY i.key_date, i.id`
+	relationsQuery = `SELECT c.id, c.name, t.type
+	FROM gcd_character_relation r
+	JOIN gcd_character c ON c.id = r.to_character_id AND c.deleted = 0
+	JOIN gcd_character_relation_type t ON t.id = r.relation_type_id
+	WHERE r.from_character_id = ? AND r.deleted = 0
+	ORDER BY r.id`
+	searchQuery = `SELECT id, name, disambiguation FROM gcd_character
+	WHERE deleted = 0 AND name LIKE ?
+	ORDER BY name, id`
+	seriesSearchQuery = `SELECT s.id, s.name, s.year_began, p.name
+	FROM gcd_series s
+	JOIN gcd_publisher p ON p.id = s.publisher_id
+	WHERE s.deleted = 0 AND s.name LIKE ?
+	ORDER BY s.name, s.year_began, s.id`
+	creatorSearchQuery = `SELECT id, gcd_official_name FROM gcd_creator
+	WHERE deleted = 0 AND gcd_official_name LIKE ?
+	ORDER BY gcd_official_name, id`
+	publisherSearchQuery = `SELECT id, name FROM gcd_publisher
+	WHERE deleted = 0 AND name LIKE ?
+	ORDER BY name, id`
+)
+
+// Reads entities from a GCD SQLite dump. It implements `externalissuesource.ExternalSource`, so the
+// URLs passed in are the same comics.org links the GCD scraper uses, such as https://www.comics.org/issue/293849/.
+type Source struct {
+	db     *sql.DB
+	onSale externalissuesource.OnSaleEstimator // Estimates the on-sale dates the dump doesn't have. Default is `externalissuesource.DefaultOnSaleEstimator` if nil.
+}
+
+// Fetches an issue from the dump.
+func (s *Source) Issue(url string) (*externalissuesource.Issue, error) {
+	return s.IssueContext(context.Background(), url)
+}
+
+// Fetches an issue from the dump. The query is canceled when the context is done.
+func (s *Source) IssueContext(ctx context.Context, url string) (*externalissuesource.Issue, error) {
+	id, err := idFromUrl(url, "issue")
+	if err != nil {
+		return nil, err
+	}
+	issue := new(externalissuesource.Issue)
+	var number, coverDate, onSaleDate, variantName, price, isbn, barcode, rating, seriesName, publishingFormat, binding string
+	var yearBegan int
+	var pageCount float64
+	err = s.db.QueryRowContext(ctx, issueQuery, id).Scan(
+		&issue.Id, &number, &coverDate, &onSaleDate, &issue.IsVariant, &variantName,
+		&price, &pageCount, &isbn, &barcode, &rating,
+		&issue.SeriesId, &seriesName, &yearBegan, &publishingFormat, &binding, &issue.VendorId, &issue.Vendor)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Unnumbered issues are recorded as `[nn]`, which the scraper leaves blank.
+	if !strings.HasPrefix(number, "[") {
+		issue.Number = number
+	}
+	issue.Series = fmt.Sprintf("%s (%d)", seriesName, yearBegan)
+	issue.VariantInfo = externalissuesource.ParseVariantInfo(variantName)
+	externalissuesource.GcdProductDetails(issue, price, pageCount, isbn, barcode, rating)
+	externalissuesource.GcdIssueDates(issue, coverDate, onSaleDate, s.onSale)
+	issue.Format = externalissuesource.GcdFormat(fmt.Sprintf("%s %s", binding, publishingFormat))
+
+	var stories, reprintedStories int
+	if err = s.db.QueryRowContext(ctx, issueReprintQuery, id).Scan(&stories, &reprintedStories); err != nil {
+		return nil, err
+	}
+	issue.IsReprint = stories > 0 && stories == reprintedStories
+
+	versionOf := new(externalissuesource.IssueLink)
+	var versionSeriesName, versionNumber, versionVariantName string
+	err = s.db.QueryRowContext(ctx, versionOfQuery, id).Scan(&versionOf.Id, &versionSeriesName, &versionNumber, &versionVariantName)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil {
+		versionOf.Url = fmt.Sprintf("%s/issue/%s/", baseUrl, versionOf.Id)
+		versionOf.Title = issueTitle(versionSeriesName, versionNumber, versionVariantName)
+		issue.VersionOf = versionOf
+		issue.OriginalIssueId = versionOf.Id
+	}
+
+	issue.Versions = make([]externalissuesource.IssueLink, 0)
+	rows, err := s.db.QueryContext(ctx, versionsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		version := externalissuesource.IssueLink{}
+		if err := rows.Scan(&version.Id, &versionSeriesName, &versionNumber, &versionVariantName); err != nil {
+			return nil, err
+		}
+		version.Url = fmt.Sprintf("%s/issue/%s/", baseUrl, version.Id)
+		version.Title = issueTitle(versionSeriesName, versionNumber, versionVariantName)
+		issue.Versions = append(issue.Versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if issue.Stories, err = s.stories(ctx, id); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// Gets the issue's comic stories with their characters. The dump doesn't have credits, so they're empty.
+func (s *Source) stories(ctx context.Context, issueId string) ([]externalissuesource.Story, error) {
+	stories := make([]externalissuesource.Story, 0)
+	storyIds := make([]string, 0)
+	rows, err := s.db.QueryContext(ctx, storiesQuery, issueId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var storyId string
+		var pageCount float64
+		var originId, originSeriesName, originNumber, originVariantName sql.NullString
+		story := externalissuesource.Story{
+			Credits:    make([]externalissuesource.Credit, 0),
+			Characters: make([]externalissuesource.CharacterLink, 0),
+		}
+		if err := rows.Scan(&storyId, &story.Title, &pageCount, &originId, &originSeriesName, &originNumber, &originVariantName); err != nil {
+			return nil, err
+		}
+		story.PageCount = int(pageCount)
+		if originId.Valid {
+			story.IsReprintOf = &externalissuesource.IssueLink{
+				Url:   fmt.Sprintf("%s/issue/%s/", baseUrl, originId.String),
+				Id:    originId.String,
+				Title: issueTitle(originSeriesName.String, originNumber.String, originVariantName.String),
+			}
+		}
+		stories = append(stories, story)
+		storyIds = append(storyIds, storyId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for idx, storyId := range storyIds {
+		characterRows, err := s.db.QueryContext(ctx, storyCharactersQuery, storyId)
+		if err != nil {
+			return nil, err
+		}
+		for characterRows.Next() {
+			var characterId, name string
+			if err := characterRows.Scan(&characterId, &name); err != nil {
+				characterRows.Close()
+				return nil, err
+			}
+			stories[idx].Characters = append(stories[idx].Characters, externalissuesource.CharacterLink{
+				Url:  fmt.Sprintf("%s/character/%s/", baseUrl, characterId),
+				Name: name,
+			})
+		}
+		err = characterRows.Err()
+		characterRows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stories, nil
+}
+
+// Fetches the series page from the dump.
+func (s *Source) Series(url string) (*externalissuesource.SeriesPage, error) {
+	return s.SeriesContext(context.Background(), url)
+}
+
+// Fetches the series page from the dump. The queries are canceled when the context is done.
+func (s *Source) SeriesContext(ctx context.Context, url string) (*externalissuesource.SeriesPage, error) {
+	id, err := idFromUrl(url, "series")
+	if err != nil {
+		return nil, err
+	}
+	seriesPage := &externalissuesource.SeriesPage{Id: id}
+	var publishingFormat, binding string
+	err = s.db.QueryRowContext(ctx, seriesQuery, id).Scan(
+		&seriesPage.Name, &seriesPage.StartYear, &publishingFormat, &binding, &seriesPage.Publisher)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	seriesPage.Format = externalissuesource.GcdFormat(fmt.Sprintf("%s %s", binding, publishingFormat))
+
+	issueLinks := make([]externalissuesource.SeriesIssueLink, 0)
+	rows, err := s.db.QueryContext(ctx, seriesIssuesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var issueId, number, variantName string
+		issueLink := externalissuesource.SeriesIssueLink{}
+		if err := rows.Scan(&issueId, &number, &issueLink.IsVariant, &variantName); err != nil {
+			return nil, err
+		}
+		issueLink.Url = fmt.Sprintf("%s/issue/%s/", baseUrl, issueId)
+		// The original issue can have a name for its cover as well, such as `Direct`.
+		if issueLink.IsVariant {
+			issueLink.VariantName = variantName
+		}
+		if !strings.HasPrefix(number, "[") {
+			issueLink.Number = number
+		}
+		issueLinks = append(issueLinks, issueLink)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	seriesPage.IssueLinks = issueLinks
+	return seriesPage, nil
+}
+
+// Fetches the publisher page from the dump.
+func (s *Source) Publisher(url string) (*externalissuesource.Publisher, error) {
+	return s.PublisherContext(context.Background(), url)
+}
+
+// Fetches the publisher page from the dump. The queries are canceled when the context is done.
+// The dump records imprints as publishers with a parent publisher.
+func (s *Source) PublisherContext(ctx context.Context, url string) (*externalissuesource.Publisher, error) {
+	id, err := idFromUrl(url, "publisher")
+	if err != nil {
+		return nil, err
+	}
+	publisher := &externalissuesource.Publisher{Id: id}
+	var parentId, parentName sql.NullString
+	err = s.db.QueryRowContext(ctx, publisherQuery, id).Scan(
+		&publisher.Name, &publisher.YearBegan, &publisher.YearEnded, &parentId, &parentName)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if parentId.Valid {
+		publisher.Parent = &externalissuesource.PublisherLink{
+			Url:  fmt.Sprintf("%s/publisher/%s/", baseUrl, parentId.String),
+			Name: parentName.String,
+		}
+	}
+
+	imprints := make([]externalissuesource.PublisherLink, 0)
+	rows, err := s.db.QueryContext(ctx, imprintsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var imprintId, name string
+		if err := rows.Scan(&imprintId, &name); err != nil {
+			return nil, err
+		}
+		imprints = append(imprints, externalissuesource.PublisherLink{
+			Url:  fmt.Sprintf("%s/publisher/%s/", baseUrl, imprintId),
+			Name: name,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	seriesLinks := make([]externalissuesource.SeriesLink, 0)
+	seriesRows, err := s.db.QueryContext(ctx, publisherSeriesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer seriesRows.Close()
+	for seriesRows.Next() {
+		var seriesId string
+		seriesLink := externalissuesource.SeriesLink{}
+		if err := seriesRows.Scan(&seriesId, &seriesLink.Name, &seriesLink.StartYear); err != nil {
+			return nil, err
+		}
+		seriesLink.Url = fmt.Sprintf("%s/series/%s/", baseUrl, seriesId)
+		seriesLinks = append(seriesLinks, seriesLink)
+	}
+	if err := seriesRows.Err(); err != nil {
+		return nil, err
+	}
+	publisher.Imprints = imprints
+	publisher.SeriesLinks = seriesLinks
+	return publisher, nil
+}
+
+// The dump doesn't have story arcs, so this always returns `externalissuesource.ErrUnsupported`.
+func (s *Source) StoryArc(url string) (*externalissuesource.StoryArc, error) {
+	return s.StoryArcContext(context.Background(), url)
+}
+
+// The dump doesn't have story arcs, so this always returns `externalissuesource.ErrUnsupported`.
+func (s *Source) StoryArcContext(ctx context.Context, url string) (*externalissuesource.StoryArc, error) {
+	return nil, externalissuesource.ErrUnsupported
+}
+
+// Fetches the character page from the dump.
+func (s *Source) CharacterPage(url string) (*externalissuesource.CharacterPage, error) {
+	return s.CharacterPageContext(context.Background(), url)
+}
+
+// Fetches the character page from the dump. The queries are canceled when the context is done.
+// The publisher is the publisher of the character's earliest appearance.
+func (s *Source) CharacterPageContext(ctx context.Context, url string) (*externalissuesource.CharacterPage, error) {
+	id, err := idFromUrl(url, "character")
+	if err != nil {
+		return nil, err
+	}
+	characterPage := new(externalissuesource.CharacterPage)
+	err = s.db.QueryRowContext(ctx, characterQuery, id).Scan(&characterPage.Name)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	issueLinks := make([]string, 0)
+	rows, err := s.db.QueryContext(ctx, appearancesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var issueId, keyDate, publisher string
+		if err := rows.Scan(&issueId, &keyDate, &publisher); err != nil {
+			return nil, err
+		}
+		if characterPage.Publisher == "" {
+			characterPage.Publisher = publisher
+		}
+		issueLinks = append(issueLinks, fmt.Sprintf("%s/issue/%s/", baseUrl, issueId))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	otherIdentities := make([]externalissuesource.CharacterLink, 0)
+	relationRows, err := s.db.QueryContext(ctx, relationsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer relationRows.Close()
+	for relationRows.Next() {
+		var characterId, name, relationType string
+		if err := relationRows.Scan(&characterId, &name, &relationType); err != nil {
+			return nil, err
+		}
+		if characterPage.OtherName == "" && strings.HasPrefix(strings.ToLower(relationType), "civilian identity") {
+			characterPage.OtherName = name
+		}
+		otherIdentities = append(otherIdentities, externalissuesource.CharacterLink{
+			Url:  fmt.Sprintf("%s/character/%s/", baseUrl, characterId),
+			Name: name,
+		})
+	}
+	if err := relationRows.Err(); err != nil {
+		return nil, err
+	}
+
+	if characterPage.Publisher != "" {
+		characterPage.Title = fmt.Sprintf("%s (%s)", characterPage.Name, characterPage.Publisher)
+	} else {
+		characterPage.Title = characterPage.Name
+	}
+	characterPage.IssueLinks = issueLinks
+	characterPage.OtherIdentities = otherIdentities
+	return characterPage, nil
+}
+
+// Fetches the character and all of its issues from the dump.
+// Issues that can't be read are reported with an `externalissuesource.IssueLinkErrors` error.
+func (s *Source) Character(url string) (*externalissuesource.Character, error) {
+	return s.CharacterContext(context.Background(), url)
+}
+
+// Same as `Character`, but stops reading the remaining issues as soon as the context is done
+// and returns the context's error. The dump is local, so the issues are read one at a time.
+func (s *Source) CharacterContext(ctx context.Context, url string) (*externalissuesource.Character, error) {
+	characterPage, err := s.CharacterPageContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	character := &externalissuesource.Character{
+		Publisher:       characterPage.Publisher,
+		Name:            characterPage.Name,
+		Issues:          make([]externalissuesource.Issue, 0, len(characterPage.IssueLinks)),
+		OtherIdentities: characterPage.OtherIdentities,
+	}
+	linkErrors := make(externalissuesource.IssueLinkErrors)
+	for _, link := range characterPage.IssueLinks {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		issue, err := s.IssueContext(ctx, link)
+		if err != nil {
+			linkErrors[link] = err
+			continue
+		}
+		character.AddIssue(*issue)
+	}
+	if len(linkErrors) > 0 {
+		return character, linkErrors
+	}
+	return character, nil
+}
+
+// Searches the characters whose names contain the query.
+func (s *Source) SearchCharacter(query string) (externalissuesource.CharacterSearchResult, error) {
+	return s.SearchCharacterContext(context.Background(), query)
+}
+
+// Searches the characters whose names contain the query. The query is canceled when the context is done.
+// Names are shown with their disambiguation like on comics.org, such as `Cyclops [Greek mythology]`.
+func (s *Source) SearchCharacterContext(ctx context.Context, query string) (externalissuesource.CharacterSearchResult, error) {
+	rows, err := s.db.QueryContext(ctx, searchQuery, fmt.Sprintf("%%%s%%", strings.TrimSpace(query)))
+	if err != nil {
+		return externalissuesource.CharacterSearchResult{}, err
+	}
+	defer rows.Close()
+	characterLinks := make([]externalissuesource.CharacterLink, 0)
+	for rows.Next() {
+		var id, name, disambiguation string
+		if err := rows.Scan(&id, &name, &disambiguation); err != nil {
+			return externalissuesource.CharacterSearchResult{}, err
+		}
+		if disambiguation != "" {
+			name = fmt.Sprintf("%s [%s]", name, disambiguation)
+		}
+		characterLinks = append(characterLinks, externalissuesource.CharacterLink{
+			Url:            fmt.Sprintf("%s/character/%s/", baseUrl, id),
+			Name:           name,
+			Id:             id,
+			Disambiguation: disambiguation,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return externalissuesource.CharacterSearchResult{}, err
+	}
+	return externalissuesource.CharacterSearchResult{Results: characterLinks}, nil
+}
+
+// Searches the entities of the type whose names contain the query.
+func (s *Source) Search(query string, searchType externalissuesource.SearchType) (externalissuesource.SearchResult, error) {
+	return s.SearchContext(context.Background(), query, searchType)
+}
+
+// Searches the entities of the type whose names contain the query. The query is canceled when the context is done.
+// The dump doesn't have story arcs, so searching them returns `externalissuesource.ErrUnsupported`.
+func (s *Source) SearchContext(ctx context.Context, query string, searchType externalissuesource.SearchType) (externalissuesource.SearchResult, error) {
+	result := externalissuesource.SearchResult{
+		Query:      strings.TrimSpace(query),
+		Type:       searchType,
+		Titles:     make([]externalissuesource.SeriesLink, 0),
+		Creators:   make([]externalissuesource.CreatorLink, 0),
+		Publishers: make([]externalissuesource.PublisherLink, 0),
+		StoryArcs:  make([]externalissuesource.StoryArcLink, 0),
+		Characters: make([]externalissuesource.CharacterLink, 0),
+	}
+	pattern := fmt.Sprintf("%%%s%%", result.Query)
+	var err error
+	switch searchType {
+	case externalissuesource.SearchTitle:
+		err = s.searchRows(ctx, seriesSearchQuery, pattern, func(rows *sql.Rows) error {
+			var id string
+			seriesLink := externalissuesource.SeriesLink{}
+			if err := rows.Scan(&id, &seriesLink.Name, &seriesLink.StartYear, &seriesLink.Publisher); err != nil {
+				return err
+			}
+			seriesLink.Url = fmt.Sprintf("%s/series/%s/", baseUrl, id)
+			result.Titles = append(result.Titles, seriesLink)
+			return nil
+		})
+	case externalissuesource.SearchCreator:
+		err = s.searchRows(ctx, creatorSearchQuery, pattern, func(rows *sql.Rows) error {
+			var id, name string
+			if err := rows.Scan(&id, &name); err != nil {
+				return err
+			}
+			result.Creators = append(result.Creators, externalissuesource.CreatorLink{Url: fmt.Sprintf("%s/creator/%s/", baseUrl, id), Name: name})
+			return nil
+		})
+	case externalissuesource.SearchPublisher:
+		err = s.searchRows(ctx, publisherSearchQuery, pattern, func(rows *sql.Rows) error {
+			var id, name string
+			if err := rows.Scan(&id, &name); err != nil {
+				return err
+			}
+			result.Publishers = append(result.Publishers, externalissuesource.PublisherLink{Url: fmt.Sprintf("%s/publisher/%s/", baseUrl, id), Name: name})
+			return nil
+		})
+	case externalissuesource.SearchCharacter:
+		var characterSearchResult externalissuesource.CharacterSearchResult
+		characterSearchResult, err = s.SearchCharacterContext(ctx, query)
+		result.Characters = characterSearchResult.Results
+	default:
+		return externalissuesource.SearchResult{}, externalissuesource.ErrUnsupported
+	}
+	if err != nil {
+		return externalissuesource.SearchResult{}, err
+	}
+	return result, nil
+}
+
+// Runs the search query with the pattern and scans each row.
+func (s *Source) searchRows(ctx context.Context, query string, pattern string, scan func(rows *sql.Rows) error) error {
+	rows, err := s.db.QueryContext(ctx, query, pattern)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Sets the estimator for the on-sale dates the dump doesn't have.
+func (s *Source) SetOnSaleEstimator(estimator externalissuesource.OnSaleEstimator) {
+	s.onSale = estimator
+}
+
+// Closes the dump's database.
+func (s *Source) Close() error {
+	return s.db.Close()
+}
+
+// Gets the issue's title like comics.org shows it, such as `Marvel Super Heroes Secret Wars #1 [Zeck Cover]`.
+func issueTitle(seriesName string, number string, variantName string) string {
+	title := fmt.Sprintf("%s #%s", seriesName, number)
+	if strings.HasPrefix(number, "[") {
+		title = fmt.Sprintf("%s %s", seriesName, number)
+	}
+	if variantName != "" {
+		title = fmt.Sprintf("%s [%s]", title, variantName)
+	}
+	return title
+}
+
+// Gets the ID from a GCD link, such as `293849` from https://www.comics.org/issue/293849/ for the `issue` kind.
+func idFromUrl(rawUrl string, kind string) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", ErrInvalidUrl
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != kind || parts[1] == "" {
+		return "", ErrInvalidUrl
+	}
+	return parts[1], nil
+}
+
+// Opens the SQLite dump at the path as read-only. Close the source when done with it.
+func Open(path string) (*Source, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Source{db: db}, nil
+}
+
+// Creates the source from an already opened dump, such as one loaded into another SQL database.
+func NewSource(db *sql.DB) externalissuesource.ExternalSource {
+	return &Source{db: db}
+}
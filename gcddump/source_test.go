@@ -0,0 +1,253 @@
+package gcddump
+
+import (
+	"context"
+	"database/sql"
+	"github.com/aimeelaplant/externalissuesource"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Loads the dump excerpt in testdata into a temporary SQLite file and opens it.
+func openTestDump(t *testing.T) (*Source, func()) {
+	dir, err := ioutil.TempDir("", "gcddump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "gcd.db")
+	schema, err := ioutil.ReadFile("./testdata/dump.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	source, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return source, func() {
+		source.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSource_Issue(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	issue, err := source.Issue("https://www.comics.org/issue/293849/")
+	assert.Nil(t, err)
+	assert.Equal(t, "293849", issue.Id)
+	assert.Equal(t, "22", issue.Number)
+	assert.Equal(t, "11105", issue.SeriesId)
+	assert.Equal(t, "Astonishing X-Men (2004)", issue.Series)
+	assert.Equal(t, "Marvel", issue.Vendor)
+	assert.Equal(t, "78", issue.VendorId)
+	assert.Equal(t, externalissuesource.Standard, issue.Format)
+	assert.Equal(t, time.October, issue.PublicationDate.Month())
+	assert.Equal(t, externalissuesource.PrecisionMonth, issue.CoverDate.Precision)
+	assert.Equal(t, time.August, issue.OnSaleDate.Month())
+	assert.Equal(t, 22, issue.OnSaleDate.Day())
+	assert.False(t, issue.IsVariant)
+	assert.False(t, issue.IsReprint)
+	assert.False(t, issue.MonthUncertain)
+	assert.Equal(t, &externalissuesource.Price{Amount: 299, Currency: "USD"}, issue.CoverPrice)
+	assert.Equal(t, 32, issue.PageCount)
+	assert.Equal(t, "75960605514102211", issue.UPC)
+	assert.Equal(t, externalissuesource.RatingTeenPlus, issue.Rating)
+	assert.Len(t, issue.Stories, 1)
+	assert.Equal(t, "Unstoppable, Part 4", issue.Stories[0].Title)
+	assert.Equal(t, 22, issue.Stories[0].PageCount)
+	assert.Equal(t, []externalissuesource.CharacterLink{{Url: "https://www.comics.org/character/1/", Name: "Cyclops"}}, issue.Stories[0].Characters)
+	assert.Nil(t, issue.Stories[0].IsReprintOf)
+}
+
+func TestSource_Issue_VariantAndReprint(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	variant, err := source.Issue("https://www.comics.org/issue/37287/")
+	assert.Nil(t, err)
+	assert.True(t, variant.IsVariant)
+	assert.Equal(t, "1", variant.Number)
+	assert.Equal(t, "37286", variant.OriginalIssueId)
+	assert.Equal(t, "Marvel Super Heroes Secret Wars #1 [Direct]", variant.VersionOf.Title)
+
+	original, err := source.Issue("https://www.comics.org/issue/37286/")
+	assert.Nil(t, err)
+	assert.Nil(t, original.VersionOf)
+	assert.Equal(t, externalissuesource.EditionDirect, original.VariantInfo.Edition)
+	assert.Len(t, original.Versions, 1)
+	assert.Equal(t, "37287", original.Versions[0].Id)
+	assert.Equal(t, "Marvel Super Heroes Secret Wars #1 [Zeck Cover]", original.Versions[0].Title)
+
+	tpb, err := source.Issue("https://www.comics.org/issue/117402/")
+	assert.Nil(t, err)
+	assert.True(t, tpb.IsReprint)
+	assert.Equal(t, "", tpb.Number)
+	assert.Equal(t, externalissuesource.TPB, tpb.Format)
+	assert.True(t, tpb.MonthUncertain)
+	assert.Equal(t, tpb.PublicationDate, tpb.OnSaleDate)
+	assert.True(t, tpb.OnSaleEstimated)
+	assert.Equal(t, "0785108223", tpb.ISBN)
+	assert.Equal(t, 304, tpb.PageCount)
+	// The cover isn't a story, and the stories are in the order they're printed.
+	assert.Len(t, tpb.Stories, 2)
+	assert.Equal(t, "Mutant Massacre", tpb.Stories[0].Title)
+	assert.Len(t, tpb.Stories[0].Characters, 2)
+	assert.Equal(t, &externalissuesource.IssueLink{Url: "https://www.comics.org/issue/5210/", Id: "5210", Title: "The Uncanny X-Men #210"}, tpb.Stories[0].IsReprintOf)
+	// Only the origin story knows its issue.
+	assert.Equal(t, "5211", tpb.Stories[1].IsReprintOf.Id)
+
+	// The on-sale date's unknown day is padded with zeros in the dump.
+	ww, err := source.Issue("https://www.comics.org/issue/9000/")
+	assert.Nil(t, err)
+	assert.Equal(t, 1986, ww.OnSaleDate.Year())
+	assert.Equal(t, time.November, ww.OnSaleDate.Month())
+}
+
+func TestSource_Issue_NotFound(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	_, err := source.Issue("https://www.comics.org/issue/9999/")
+	assert.Equal(t, ErrNotFound, err)
+	_, err = source.Issue("https://www.comics.org/series/11105/")
+	assert.Equal(t, ErrInvalidUrl, err)
+}
+
+func TestSource_Series(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	series, err := source.Series("https://www.comics.org/series/2993/")
+	assert.Nil(t, err)
+	assert.Equal(t, "2993", series.Id)
+	assert.Equal(t, "Marvel Super Heroes Secret Wars", series.Name)
+	assert.Equal(t, 1984, series.StartYear)
+	assert.Equal(t, "Marvel", series.Publisher)
+	assert.Equal(t, externalissuesource.Standard, series.Format)
+	assert.Equal(t, []externalissuesource.SeriesIssueLink{
+		{Url: "https://www.comics.org/issue/37286/", Number: "1"},
+		{Url: "https://www.comics.org/issue/37287/", Number: "1", IsVariant: true, VariantName: "Zeck Cover"},
+	}, series.IssueLinks)
+
+	// The deleted issue isn't listed.
+	series, err = source.Series("https://www.comics.org/series/1/")
+	assert.Nil(t, err)
+	assert.Len(t, series.IssueLinks, 1)
+
+	_, err = source.Series("https://www.comics.org/series/404/")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSource_Publisher(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	publisher, err := source.Publisher("https://www.comics.org/publisher/78/")
+	assert.Nil(t, err)
+	assert.Equal(t, "78", publisher.Id)
+	assert.Equal(t, "Marvel", publisher.Name)
+	assert.Nil(t, publisher.Parent)
+	assert.Equal(t, 1939, publisher.YearBegan)
+	assert.Equal(t, 0, publisher.YearEnded)
+	// The deleted imprint isn't listed.
+	assert.Equal(t, []externalissuesource.PublisherLink{{Url: "https://www.comics.org/publisher/2310/", Name: "Epic"}}, publisher.Imprints)
+	assert.Len(t, publisher.SeriesLinks, 5)
+	assert.Equal(t, externalissuesource.SeriesLink{Url: "https://www.comics.org/series/11105/", Name: "Astonishing X-Men", StartYear: 2004}, publisher.SeriesLinks[0])
+
+	imprint, err := source.Publisher("https://www.comics.org/publisher/2310/")
+	assert.Nil(t, err)
+	assert.Equal(t, &externalissuesource.PublisherLink{Url: "https://www.comics.org/publisher/78/", Name: "Marvel"}, imprint.Parent)
+	assert.Equal(t, 1996, imprint.YearEnded)
+	assert.Len(t, imprint.SeriesLinks, 1)
+
+	_, err = source.Publisher("https://www.comics.org/publisher/404/")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSource_CharacterPage(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	c, err := source.CharacterPage("https://www.comics.org/character/1/")
+	assert.Nil(t, err)
+	assert.Equal(t, "Cyclops", c.Name)
+	assert.Equal(t, "Marvel", c.Publisher)
+	assert.Equal(t, "Cyclops (Marvel)", c.Title)
+	assert.Equal(t, "Scott Summers", c.OtherName)
+	assert.Equal(t, []string{
+		"https://www.comics.org/issue/1950/",
+		"https://www.comics.org/issue/37287/",
+		"https://www.comics.org/issue/117402/",
+		"https://www.comics.org/issue/293849/",
+	}, c.IssueLinks)
+	assert.Len(t, c.OtherIdentities, 2)
+	assert.Equal(t, "https://www.comics.org/character/40519/", c.OtherIdentities[1].Url)
+}
+
+func TestSource_Character(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	c, err := source.Character("https://www.comics.org/character/1/")
+	assert.Nil(t, err)
+	assert.Equal(t, "Cyclops", c.Name)
+	assert.Len(t, c.Issues, 4)
+	assert.Len(t, c.OtherIdentities, 2)
+	assert.Equal(t, "1950", c.Issues[0].Id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c, err = source.CharacterContext(ctx, "https://www.comics.org/character/1/")
+	assert.Nil(t, c)
+	assert.Error(t, err)
+}
+
+func TestSource_SearchCharacter(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	result, err := source.SearchCharacter(" cyclops ")
+	assert.Nil(t, err)
+	assert.Len(t, result.Results, 2)
+	assert.Equal(t, "Cyclops", result.Results[0].Name)
+	assert.Equal(t, "https://www.comics.org/character/1/", result.Results[0].Url)
+	assert.Equal(t, "Cyclops [Greek mythology]", result.Results[1].Name)
+	assert.Equal(t, "18421", result.Results[1].Id)
+	assert.Equal(t, "Greek mythology", result.Results[1].Disambiguation)
+}
+
+func TestSource_Search(t *testing.T) {
+	source, cleanup := openTestDump(t)
+	defer cleanup()
+	result, err := source.Search("x-men", externalissuesource.SearchTitle)
+	assert.Nil(t, err)
+	assert.Len(t, result.Titles, 4)
+	assert.Equal(t, externalissuesource.SeriesLink{Url: "https://www.comics.org/series/11105/", Name: "Astonishing X-Men", StartYear: 2004, Publisher: "Marvel"}, result.Titles[0])
+
+	// The deleted creator isn't listed.
+	result, err = source.Search("cassaday", externalissuesource.SearchCreator)
+	assert.Nil(t, err)
+	assert.Equal(t, []externalissuesource.CreatorLink{{Url: "https://www.comics.org/creator/1734/", Name: "John Cassaday"}}, result.Creators)
+
+	result, err = source.Search("epic", externalissuesource.SearchPublisher)
+	assert.Nil(t, err)
+	assert.Equal(t, []externalissuesource.PublisherLink{{Url: "https://www.comics.org/publisher/2310/", Name: "Epic"}}, result.Publishers)
+
+	result, err = source.Search(" cyclops ", externalissuesource.SearchCharacter)
+	assert.Nil(t, err)
+	assert.Equal(t, "cyclops", result.Query)
+	assert.Len(t, result.Characters, 2)
+
+	_, err = source.Search("unstoppable", externalissuesource.SearchStoryArc)
+	assert.Equal(t, externalissuesource.ErrUnsupported, err)
+}
+
+func TestOpen_Missing(t *testing.T) {
+	_, err := Open("./testdata/missing.db")
+	assert.Error(t, err)
+}
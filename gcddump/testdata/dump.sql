@@ -0,0 +1,155 @@
+-- A small excerpt of the GCD public SQLite dump with only the tables and columns read by the importer.
+CREATE TABLE gcd_publisher (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  year_began INTEGER,
+  year_ended INTEGER,
+  parent_id INTEGER,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_series (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  year_began INTEGER NOT NULL,
+  publisher_id INTEGER NOT NULL,
+  publishing_format VARCHAR(255) NOT NULL DEFAULT '',
+  binding VARCHAR(255) NOT NULL DEFAULT '',
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_issue (
+  id INTEGER PRIMARY KEY,
+  number VARCHAR(50) NOT NULL,
+  series_id INTEGER NOT NULL,
+  publication_date VARCHAR(255) NOT NULL DEFAULT '',
+  key_date VARCHAR(10) NOT NULL DEFAULT '',
+  on_sale_date VARCHAR(10) NOT NULL DEFAULT '',
+  variant_of_id INTEGER,
+  variant_name VARCHAR(255) NOT NULL DEFAULT '',
+  price VARCHAR(255) NOT NULL DEFAULT '',
+  page_count DECIMAL(10,3),
+  isbn VARCHAR(32) NOT NULL DEFAULT '',
+  barcode VARCHAR(38) NOT NULL DEFAULT '',
+  rating VARCHAR(255) NOT NULL DEFAULT '',
+  sort_code INTEGER NOT NULL DEFAULT 0,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_story_type (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(50) NOT NULL
+);
+CREATE TABLE gcd_story (
+  id INTEGER PRIMARY KEY,
+  issue_id INTEGER NOT NULL,
+  type_id INTEGER NOT NULL,
+  title VARCHAR(255) NOT NULL DEFAULT '',
+  page_count DECIMAL(10,3),
+  sequence_number INTEGER NOT NULL DEFAULT 0,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_reprint (
+  id INTEGER PRIMARY KEY,
+  origin_id INTEGER,
+  target_id INTEGER,
+  origin_issue_id INTEGER,
+  target_issue_id INTEGER
+);
+CREATE TABLE gcd_character (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  disambiguation VARCHAR(255) NOT NULL DEFAULT '',
+  year_first_published INTEGER,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_character_relation_type (
+  id INTEGER PRIMARY KEY,
+  type VARCHAR(255) NOT NULL
+);
+CREATE TABLE gcd_character_relation (
+  id INTEGER PRIMARY KEY,
+  from_character_id INTEGER NOT NULL,
+  to_character_id INTEGER NOT NULL,
+  relation_type_id INTEGER NOT NULL,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_story_character (
+  id INTEGER PRIMARY KEY,
+  story_id INTEGER NOT NULL,
+  character_id INTEGER NOT NULL,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+CREATE TABLE gcd_creator (
+  id INTEGER PRIMARY KEY,
+  gcd_official_name VARCHAR(255) NOT NULL,
+  deleted TINYINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO gcd_publisher (id, name, year_began, year_ended, parent_id, deleted) VALUES
+  (78, 'Marvel', 1939, NULL, NULL, 0),
+  (54, 'DC', 1935, NULL, NULL, 0),
+  (2310, 'Epic', 1982, 1996, 78, 0),
+  (3872, 'Icon', 2004, NULL, 78, 1);
+
+INSERT INTO gcd_series (id, name, year_began, publisher_id, publishing_format, binding) VALUES
+  (1, 'The X-Men', 1963, 78, 'was ongoing series', 'saddle-stitched'),
+  (2993, 'Marvel Super Heroes Secret Wars', 1984, 78, 'limited series', 'saddle-stitched'),
+  (11105, 'Astonishing X-Men', 2004, 78, 'was ongoing series', 'saddle-stitched'),
+  (44322, 'X-Men: Mutant Massacre', 2001, 78, 'one-shot', 'Trade Paperback'),
+  (8000, 'Wonder Woman', 1987, 54, 'was ongoing series', 'saddle-stitched'),
+  (3460, 'Elektra: Assassin', 1986, 2310, 'limited series', 'saddle-stitched'),
+  (2525, 'The Uncanny X-Men', 1981, 78, 'was ongoing series', 'saddle-stitched');
+
+INSERT INTO gcd_issue (id, number, series_id, publication_date, key_date, on_sale_date, variant_of_id, variant_name, price, page_count, isbn, barcode, rating) VALUES
+  (1950, '1', 1, 'September 1963', '1963-09-00', '1963-07-02', NULL, '', '0.12 USD', 36, '', '', ''),
+  (37286, '1', 2993, 'May 1984', '1984-05-00', '1984-02-14', NULL, 'Direct', '0.75 USD', 36, '', '', ''),
+  (37287, '1', 2993, 'May 1984', '1984-05-00', '1984-02-14', 37286, 'Zeck Cover', '0.75 USD', 36, '', '', ''),
+  (293849, '22', 11105, 'October 2007', '2007-10-00', '2007-08-22', NULL, '', '2.99 USD; 3.75 CAD', 32, '', '75960605514102211', 'Rated T+'),
+  (117402, '[nn]', 44322, 'Summer 2001', '2001-06-00', '', NULL, '', '24.95 USD', 304, '0-7851-0822-3', '', ''),
+  (9000, '1', 8000, 'February 1987', '1987-02-00', '1986-11-00', NULL, '', '0.75 USD', NULL, '', '', ''),
+  (5210, '210', 2525, 'October 1986', '1986-10-00', '1986-07-08', NULL, '', '0.75 USD', 36, '', '', ''),
+  (5211, '211', 2525, 'November 1986', '1986-11-00', '1986-08-05', NULL, '', '0.75 USD', 36, '', '', '');
+INSERT INTO gcd_issue (id, number, series_id, publication_date, key_date, on_sale_date, deleted) VALUES
+  (9999, '2', 1, 'November 1963', '1963-11-00', '1963-09-03', 1);
+
+INSERT INTO gcd_story_type (id, name) VALUES (6, 'cover'), (19, 'comic story');
+
+INSERT INTO gcd_story (id, issue_id, type_id, title, page_count, sequence_number) VALUES
+  (100, 1950, 6, 'X-Men', 1, 0),
+  (101, 1950, 19, 'X-Men', 22, 1),
+  (200, 37287, 19, 'The War Begins', 36, 1),
+  (300, 293849, 19, 'Unstoppable, Part 4', 22, 1),
+  (400, 117402, 6, '', 1, 0),
+  (402, 117402, 19, 'Massacre Aftermath', 23, 2),
+  (401, 117402, 19, 'Mutant Massacre', 23, 1),
+  (500, 5210, 19, 'The Morning After', 23, 1),
+  (501, 5211, 19, 'Massacre Aftermath', 23, 1);
+
+INSERT INTO gcd_reprint (id, origin_id, target_id, origin_issue_id, target_issue_id) VALUES
+  (1, 500, 401, 5210, 117402),
+  (2, 501, 402, NULL, 117402);
+
+INSERT INTO gcd_character (id, name, disambiguation, year_first_published) VALUES
+  (1, 'Cyclops', '', 1963),
+  (2811, 'Scott Summers', '', 1963),
+  (40519, 'Slym Dayspring', '', 1994),
+  (18421, 'Cyclops', 'Greek mythology', 1987),
+  (2, 'Wonder Woman', '', 1941);
+INSERT INTO gcd_character (id, name, disambiguation, year_first_published, deleted) VALUES
+  (3, 'Cyclops', 'Duplicate', 1963, 1);
+
+INSERT INTO gcd_character_relation_type (id, type) VALUES (1, 'civilian identity'), (2, 'alternate identity');
+
+INSERT INTO gcd_character_relation (id, from_character_id, to_character_id, relation_type_id) VALUES
+  (1, 1, 2811, 1),
+  (2, 1, 40519, 2);
+
+INSERT INTO gcd_story_character (id, story_id, character_id) VALUES
+  (1, 101, 1),
+  (2, 200, 1),
+  (3, 300, 1),
+  (4, 401, 1),
+  (5, 401, 2811);
+
+INSERT INTO gcd_creator (id, gcd_official_name, deleted) VALUES
+  (6024, 'Joss Whedon', 0),
+  (1734, 'John Cassaday', 0),
+  (1735, 'John Cassaday', 1);
@@ -0,0 +1,74 @@
+// Package httpcache caches raw HTTP responses beneath an `http.Client`, so pages that rarely change
+// aren't downloaded again. Stale responses are revalidated with ETag/Last-Modified when the server supports it.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Stores raw responses keyed by URL.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte) error
+	Delete(key string) error
+}
+
+// Stores each response in its own file in a directory. The file name is the SHA-256 hash of the key,
+// so any URL can be stored safely.
+type DiskCache struct {
+	dir string
+}
+
+// Gets the stored response for the key.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	value, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Stores the response for the key. The file is written to a temporary file first and then renamed,
+// so concurrent readers never see a partially written response.
+func (c *DiskCache) Set(key string, value []byte) error {
+	file, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(value); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), c.path(key))
+}
+
+// Removes the stored response for the key.
+func (c *DiskCache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *DiskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:]))
+}
+
+// Creates a disk cache in the directory, creating the directory if it doesn't exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
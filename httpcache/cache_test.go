@@ -0,0 +1,32 @@
+package httpcache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpcache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cache, err := NewDiskCache(filepath.Join(dir, "pages"))
+	assert.Nil(t, err)
+	_, ok := cache.Get("https://comicbookdb.com/issue.php?ID=1")
+	assert.False(t, ok)
+
+	assert.Nil(t, cache.Set("https://comicbookdb.com/issue.php?ID=1", []byte("page")))
+	value, ok := cache.Get("https://comicbookdb.com/issue.php?ID=1")
+	assert.True(t, ok)
+	assert.Equal(t, "page", string(value))
+	_, ok = cache.Get("https://comicbookdb.com/issue.php?ID=2")
+	assert.False(t, ok)
+
+	assert.Nil(t, cache.Delete("https://comicbookdb.com/issue.php?ID=1"))
+	_, ok = cache.Get("https://comicbookdb.com/issue.php?ID=1")
+	assert.False(t, ok)
+	assert.Nil(t, cache.Delete("https://comicbookdb.com/issue.php?ID=1"))
+}
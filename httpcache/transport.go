@@ -0,0 +1,185 @@
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+const (
+	// The header on cached responses with when the response was stored or last revalidated.
+	storedAtHeader = "X-Httpcache-Stored-At"
+	// The header set to "1" on responses served from the cache.
+	FromCacheHeader = "X-From-Cache"
+)
+
+// An `http.RoundTripper` that serves GET requests from the cache while they're fresh.
+// Once a response is older than its TTL, it's revalidated with `If-None-Match`/`If-Modified-Since`
+// if the response had an ETag or Last-Modified header, or downloaded again otherwise.
+// Only 200 responses are stored, and only if `Valid` accepts them.
+type Transport struct {
+	Cache Cache
+	// Gets how long the response for the request stays fresh. Requests with a TTL of 0 or less aren't cached.
+	TTL func(req *http.Request) time.Duration
+	// Gets the cache key for the request, such as to keep the pages for different sessions apart.
+	// Default is the request's URL if not provided.
+	Key func(req *http.Request) string
+	// Whether the 200 response with the body is worth storing. Sites that serve their error pages as a 200
+	// can reject them here, so they're neither stored nor served from the cache. Every 200 response is valid if not provided.
+	Valid func(resp *http.Response, body []byte) bool
+	// The transport that sends the requests. Default is `http.DefaultTransport` if not provided.
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base().RoundTrip(req)
+	}
+	ttl := t.TTL(req)
+	if ttl <= 0 {
+		return t.base().RoundTrip(req)
+	}
+	key := req.URL.String()
+	if t.Key != nil {
+		key = t.Key(req)
+	}
+	cached, storedAt, ok := t.load(key, req)
+	if !ok {
+		return t.fetch(key, req)
+	}
+	if time.Since(storedAt) < ttl {
+		cached.Header.Set(FromCacheHeader, "1")
+		return cached, nil
+	}
+
+	etag := cached.Header.Get("ETag")
+	lastModified := cached.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		cached.Body.Close()
+		return t.fetch(key, req)
+	}
+	revalidate := cloneRequest(req)
+	if etag != "" {
+		revalidate.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		revalidate.Header.Set("If-Modified-Since", lastModified)
+	}
+	resp, err := t.base().RoundTrip(revalidate)
+	if err != nil {
+		cached.Body.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusNotModified {
+		cached.Body.Close()
+		return t.store(key, resp)
+	}
+	resp.Body.Close()
+	// Still the same page, so keep it for another TTL.
+	for _, header := range []string{"ETag", "Last-Modified", "Cache-Control", "Expires", "Date"} {
+		if value := resp.Header.Get(header); value != "" {
+			cached.Header.Set(header, value)
+		}
+	}
+	t.save(key, cached)
+	cached.Header.Set(FromCacheHeader, "1")
+	return cached, nil
+}
+
+// Sends the request and stores the response if it's a 200.
+func (t *Transport) fetch(key string, req *http.Request) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.store(key, resp)
+}
+
+// Stores the response if it's a valid 200. The body is read up front so a failed download is never stored.
+// An invalid response also removes the stored one, since it replaces it.
+func (t *Transport) store(key string, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if !t.valid(resp, body) {
+		t.Cache.Delete(key)
+		return resp, nil
+	}
+	t.save(key, resp)
+	return resp, nil
+}
+
+func (t *Transport) valid(resp *http.Response, body []byte) bool {
+	return t.Valid == nil || t.Valid(resp, body)
+}
+
+// Writes the raw response to the cache with the current time. The response's body can still be read
+// afterwards. The cache is best effort, so a failed write only means the page is downloaded again next time.
+func (t *Transport) save(key string, resp *http.Response) {
+	resp.Header.Set(storedAtHeader, time.Now().UTC().Format(time.RFC3339Nano))
+	defer resp.Header.Del(storedAtHeader)
+	resp.Header.Del(FromCacheHeader)
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return
+	}
+	t.Cache.Set(key, dump)
+}
+
+// Reads the cached response for the key and when it was stored.
+func (t *Transport) load(key string, req *http.Request) (*http.Response, time.Time, bool) {
+	dump, ok := t.Cache.Get(key)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(dump)), req)
+	if err != nil {
+		t.Cache.Delete(key)
+		return nil, time.Time{}, false
+	}
+	storedAt, err := time.Parse(time.RFC3339Nano, resp.Header.Get(storedAtHeader))
+	if err != nil {
+		resp.Body.Close()
+		t.Cache.Delete(key)
+		return nil, time.Time{}, false
+	}
+	resp.Header.Del(storedAtHeader)
+	// Drop responses stored before `Valid` rejected them.
+	if t.Valid != nil {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || !t.Valid(resp, body) {
+			t.Cache.Delete(key)
+			return nil, time.Time{}, false
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return resp, storedAt, true
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// Copies the request with its own headers, since a RoundTripper mustn't modify the caller's request.
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		clone.Header[key] = append([]string(nil), values...)
+	}
+	return clone.WithContext(req.Context())
+}
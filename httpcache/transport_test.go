@@ -0,0 +1,208 @@
+package httpcache
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Stores the values in memory.
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *memoryCache) Set(key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func newClient(ttl time.Duration) *http.Client {
+	return &http.Client{Transport: &Transport{
+		Cache: &memoryCache{values: make(map[string][]byte)},
+		TTL: func(req *http.Request) time.Duration {
+			return ttl
+		},
+	}}
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	resp, err := client.Get(url)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	return resp, string(body)
+}
+
+func TestTransport_Fresh(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, "page %d", requests)
+	}))
+	defer ts.Close()
+	client := newClient(time.Hour)
+
+	resp, body := get(t, client, ts.URL)
+	assert.Equal(t, "page 1", body)
+	assert.Equal(t, "", resp.Header.Get(FromCacheHeader))
+	resp, body = get(t, client, ts.URL)
+	assert.Equal(t, "page 1", body)
+	assert.Equal(t, "1", resp.Header.Get(FromCacheHeader))
+	assert.Equal(t, "", resp.Header.Get(storedAtHeader))
+	assert.Equal(t, 1, requests)
+}
+
+func TestTransport_Revalidate(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintf(w, "page %d", requests)
+	}))
+	defer ts.Close()
+	client := newClient(time.Nanosecond)
+
+	_, body := get(t, client, ts.URL)
+	assert.Equal(t, "page 1", body)
+	resp, body := get(t, client, ts.URL)
+	assert.Equal(t, "page 1", body)
+	assert.Equal(t, "1", resp.Header.Get(FromCacheHeader))
+	assert.Equal(t, 2, requests)
+}
+
+func TestTransport_NoValidators(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "", r.Header.Get("If-None-Match"))
+		assert.Equal(t, "", r.Header.Get("If-Modified-Since"))
+		fmt.Fprintf(w, "page %d", requests)
+	}))
+	defer ts.Close()
+	client := newClient(time.Nanosecond)
+
+	_, body := get(t, client, ts.URL)
+	assert.Equal(t, "page 1", body)
+	resp, body := get(t, client, ts.URL)
+	assert.Equal(t, "page 2", body)
+	assert.Equal(t, "", resp.Header.Get(FromCacheHeader))
+}
+
+func TestTransport_NotStored(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprintf(w, "page %d", requests)
+	}))
+	defer ts.Close()
+
+	client := newClient(time.Hour)
+	resp, _ := get(t, client, ts.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp, body := get(t, client, ts.URL)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "page 2", body)
+
+	// Requests with no TTL always go to the server.
+	client = newClient(0)
+	get(t, client, ts.URL)
+	_, body = get(t, client, ts.URL)
+	assert.Equal(t, "page 4", body)
+}
+
+func TestTransport_Invalid(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			fmt.Fprint(w, "outage")
+			return
+		}
+		fmt.Fprintf(w, "page %d", requests)
+	}))
+	defer ts.Close()
+	cache := &memoryCache{values: make(map[string][]byte)}
+	client := &http.Client{Transport: &Transport{
+		Cache: cache,
+		TTL: func(req *http.Request) time.Duration {
+			return time.Hour
+		},
+		Valid: func(resp *http.Response, body []byte) bool {
+			return string(body) != "outage"
+		},
+	}}
+
+	// The outage page is returned but not stored.
+	_, body := get(t, client, ts.URL)
+	assert.Equal(t, "outage", body)
+	assert.Len(t, cache.values, 0)
+	_, body = get(t, client, ts.URL)
+	assert.Equal(t, "page 2", body)
+	resp, body := get(t, client, ts.URL)
+	assert.Equal(t, "page 2", body)
+	assert.Equal(t, "1", resp.Header.Get(FromCacheHeader))
+
+	// An invalid page that was already stored is dropped.
+	for key := range cache.values {
+		cache.values[key] = []byte("HTTP/1.1 200 OK\r\nX-Httpcache-Stored-At: " + time.Now().UTC().Format(time.RFC3339Nano) + "\r\n\r\noutage")
+	}
+	_, body = get(t, client, ts.URL)
+	assert.Equal(t, "page 3", body)
+}
+
+func TestTransport_Key(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, "page %d for %s", requests, r.Header.Get("X-Session"))
+	}))
+	defer ts.Close()
+	client := &http.Client{Transport: &Transport{
+		Cache: &memoryCache{values: make(map[string][]byte)},
+		TTL: func(req *http.Request) time.Duration {
+			return time.Hour
+		},
+		Key: func(req *http.Request) string {
+			return req.URL.String() + "#" + req.Header.Get("X-Session")
+		},
+	}}
+	for _, session := range []string{"", "logged-in", ""} {
+		req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+		assert.Nil(t, err)
+		req.Header.Set("X-Session", session)
+		resp, err := client.Do(req)
+		assert.Nil(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, 2, requests)
+}
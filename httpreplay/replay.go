@@ -0,0 +1,212 @@
+// Package httpreplay records live HTTP responses into cassette files and replays them later, so the sources
+// can be tested end-to-end without the network and the fixtures can be refreshed from the real site in one step.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// Set this environment variable to any value to record the cassettes from the live site instead of replaying them,
+// such as `HTTPREPLAY_RECORD=1 go test ./...`.
+const RecordEnv = "HTTPREPLAY_RECORD"
+
+// The body encoding for responses that aren't valid UTF-8, such as the cb pages in ISO-8859-1.
+const base64Encoding = "base64"
+
+var ErrNotRecorded = errors.New("request was not recorded in the cassette")
+
+// Whether a recorder replays a cassette or records a new one.
+type Mode int
+
+const (
+	// Serves the responses from the cassette. Requests that weren't recorded fail with `ErrNotRecorded`.
+	ModeReplay Mode = iota
+	// Sends the requests to the live site and records the responses. `Save` replaces the cassette.
+	ModeRecord
+)
+
+// Gets `ModeRecord` if the `HTTPREPLAY_RECORD` environment variable is set, or `ModeReplay` otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// The recorded interactions, stored as JSON.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// A request and the response it got. Only the method and URL of the request are kept, so cookies and
+// other credentials sent with the request never end up in a cassette.
+type Interaction struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Status       int         `json:"status"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // "base64" if the body isn't valid UTF-8.
+}
+
+// Gets the key that requests are matched by.
+func (i *Interaction) key() string {
+	return fmt.Sprintf("%s %s", i.Method, i.URL)
+}
+
+// An `http.RoundTripper` that replays or records a cassette.
+type Recorder struct {
+	path     string
+	mode     Mode
+	base     http.RoundTripper
+	mu       sync.Mutex
+	cassette Cassette
+	replayed map[string]int // The number of times each request has been replayed.
+}
+
+// Serves the request from the cassette or sends it to the live site and records it, depending on the mode.
+// When a request is made more than once, the recorded responses are replayed in order and the last one is
+// repeated after that.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := fmt.Sprintf("%s %s", req.Method, req.URL.String())
+	r.mu.Lock()
+	matches := make([]Interaction, 0, 1)
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.key() == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+	}
+	idx := r.replayed[key]
+	if idx >= len(matches) {
+		idx = len(matches) - 1
+	}
+	r.replayed[key]++
+	r.mu.Unlock()
+
+	interaction := matches[idx]
+	body := []byte(interaction.Body)
+	if interaction.BodyEncoding == base64Encoding {
+		decoded, err := base64.StdEncoding.DecodeString(interaction.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+	header := make(http.Header, len(interaction.Headers))
+	for name, values := range interaction.Headers {
+		header[name] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Status:  resp.StatusCode,
+		Headers: make(http.Header),
+		Body:    string(body),
+	}
+	for name, values := range resp.Header {
+		// The site's session cookies aren't needed to replay the page.
+		if name != "Set-Cookie" {
+			interaction.Headers[name] = append([]string(nil), values...)
+		}
+	}
+	if !utf8.Valid(body) {
+		interaction.Body = base64.StdEncoding.EncodeToString(body)
+		interaction.BodyEncoding = base64Encoding
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Writes the recorded interactions to the cassette, creating its directory if needed. Interactions are sorted
+// by URL so concurrent requests are stored in the same order every time. It does nothing when replaying.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.SliceStable(r.cassette.Interactions, func(i, j int) bool {
+		return r.cassette.Interactions[i].key() < r.cassette.Interactions[j].key()
+	})
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.cassette); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, buf.Bytes(), 0644)
+}
+
+// Creates a recorder for the cassette at the path. When replaying, the cassette is read up front.
+// When recording, the requests are sent with the base transport, or `http.DefaultTransport` if it's nil,
+// and the cassette is only written by `Save`.
+func New(path string, mode Mode, base http.RoundTripper) (*Recorder, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	recorder := &Recorder{path: path, mode: mode, base: base, replayed: make(map[string]int)}
+	if mode == ModeRecord {
+		return recorder, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &recorder.cassette); err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
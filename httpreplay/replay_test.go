@@ -0,0 +1,90 @@
+package httpreplay
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	resp, err := client.Get(url)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	return resp, string(body)
+}
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpreplay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassettes", "test.json")
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.SetCookie(w, &http.Cookie{Name: "PHPSESSID", Value: "secret"})
+		w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+		switch r.URL.Path {
+		case "/latin1":
+			w.Write([]byte{'c', 'a', 'f', 0xe9})
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			fmt.Fprintf(w, "<p>page %d</p>", requests)
+		}
+	}))
+
+	recorder, err := New(path, ModeRecord, nil)
+	assert.Nil(t, err)
+	client := &http.Client{Transport: recorder}
+	_, body := get(t, client, fmt.Sprintf("%s/page", ts.URL))
+	assert.Equal(t, "<p>page 1</p>", body)
+	get(t, client, fmt.Sprintf("%s/page", ts.URL))
+	get(t, client, fmt.Sprintf("%s/latin1", ts.URL))
+	get(t, client, fmt.Sprintf("%s/missing", ts.URL))
+	assert.Nil(t, recorder.Save())
+	ts.Close()
+
+	recorder, err = New(path, ModeReplay, nil)
+	assert.Nil(t, err)
+	assert.Len(t, recorder.cassette.Interactions, 4)
+	client = &http.Client{Transport: recorder}
+	resp, body := get(t, client, fmt.Sprintf("%s/page", ts.URL))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "<p>page 1</p>", body)
+	assert.Equal(t, "text/html; charset=ISO-8859-1", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "", resp.Header.Get("Set-Cookie"))
+	_, body = get(t, client, fmt.Sprintf("%s/page", ts.URL))
+	assert.Equal(t, "<p>page 2</p>", body)
+	_, body = get(t, client, fmt.Sprintf("%s/page", ts.URL))
+	assert.Equal(t, "<p>page 2</p>", body)
+	_, body = get(t, client, fmt.Sprintf("%s/latin1", ts.URL))
+	assert.Equal(t, string([]byte{'c', 'a', 'f', 0xe9}), body)
+	resp, _ = get(t, client, fmt.Sprintf("%s/missing", ts.URL))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, 4, requests)
+
+	_, err = client.Get(fmt.Sprintf("%s/other", ts.URL))
+	assert.True(t, errors.Is(err, ErrNotRecorded))
+}
+
+func TestNew_MissingCassette(t *testing.T) {
+	_, err := New("./testdata/missing.json", ModeReplay, nil)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestModeFromEnv(t *testing.T) {
+	os.Unsetenv(RecordEnv)
+	assert.Equal(t, ModeReplay, ModeFromEnv())
+	os.Setenv(RecordEnv, "1")
+	defer os.Unsetenv(RecordEnv)
+	assert.Equal(t, ModeRecord, ModeFromEnv())
+}
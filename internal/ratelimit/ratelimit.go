@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// A token bucket rate limiter that keeps a separate bucket for each host.
+type Limiter struct {
+	rate    float64       // The number of tokens added to a bucket per second.
+	burst   int           // The maximum number of tokens a bucket can hold.
+	jitter  time.Duration // The maximum random delay added to each wait.
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// Creates a limiter that allows `rate` requests per second to each host, with up to `burst` requests at once.
+// Each wait is delayed by a random duration up to `jitter` so concurrent callers don't fire in lockstep.
+func New(rate float64, burst int, jitter time.Duration) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:    rate,
+		burst:   burst,
+		jitter:  jitter,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Blocks until a request to the host is allowed or the context is done.
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	delay := l.reserve(host)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(host)
+		return ctx.Err()
+	}
+}
+
+// Holds off every request to the host until the time, such as when the host responds with `Retry-After`.
+func (l *Limiter) BlockUntil(host string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(host, time.Now())
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// Takes a token from the host's bucket and returns how long to wait before using it.
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b := l.bucket(host, now)
+	if l.rate > 0 {
+		b.tokens += now.Sub(b.last).Seconds() * l.rate
+		if b.tokens > float64(l.burst) {
+			b.tokens = float64(l.burst)
+		}
+	}
+	b.last = now
+	b.tokens--
+
+	var delay time.Duration
+	if b.tokens < 0 && l.rate > 0 {
+		delay = time.Duration(-b.tokens / l.rate * float64(time.Second))
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > delay {
+		delay = blocked
+	}
+	if l.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(l.jitter)))
+	}
+	return delay
+}
+
+// Gives back the token of a wait that was canceled.
+func (l *Limiter) cancel(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets[host].tokens++
+}
+
+// Gets the host's bucket, creating a full one if it doesn't exist. The lock must be held.
+func (l *Limiter) bucket(host string, now time.Time) *bucket {
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[host] = b
+	}
+	return b
+}
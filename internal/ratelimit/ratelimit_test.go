@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Wait(t *testing.T) {
+	limiter := New(20, 2, 0)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, limiter.Wait(context.Background(), "comicbookdb.com"))
+	}
+	// The burst of 2 is free, then each request waits 50ms for a token.
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+
+	// Other hosts have their own bucket.
+	start = time.Now()
+	assert.Nil(t, limiter.Wait(context.Background(), "www.comics.org"))
+	assert.True(t, time.Since(start) < 40*time.Millisecond)
+}
+
+func TestLimiter_BlockUntil(t *testing.T) {
+	limiter := New(1000, 10, 0)
+	limiter.BlockUntil("comicbookdb.com", time.Now().Add(60*time.Millisecond))
+	start := time.Now()
+	assert.Nil(t, limiter.Wait(context.Background(), "comicbookdb.com"))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestLimiter_Wait_Canceled(t *testing.T) {
+	limiter := New(1, 1, 0)
+	assert.Nil(t, limiter.Wait(context.Background(), "comicbookdb.com"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx, "comicbookdb.com"))
+}
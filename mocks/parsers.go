@@ -155,6 +155,150 @@ func (mr *MockExternalCharacterSearchParserMockRecorder) CharacterSearch(body in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharacterSearch", reflect.TypeOf((*MockExternalCharacterSearchParser)(nil).CharacterSearch), body)
 }
 
+// MockExternalSearchParser is a mock of ExternalSearchParser interface
+type MockExternalSearchParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockExternalSearchParserMockRecorder
+}
+
+// MockExternalSearchParserMockRecorder is the mock recorder for MockExternalSearchParser
+type MockExternalSearchParserMockRecorder struct {
+	mock *MockExternalSearchParser
+}
+
+// NewMockExternalSearchParser creates a new mock instance
+func NewMockExternalSearchParser(ctrl *gomock.Controller) *MockExternalSearchParser {
+	mock := &MockExternalSearchParser{ctrl: ctrl}
+	mock.recorder = &MockExternalSearchParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExternalSearchParser) EXPECT() *MockExternalSearchParserMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method
+func (m *MockExternalSearchParser) Search(body io.Reader, searchType externalissuesource.SearchType) (*externalissuesource.SearchResult, error) {
+	ret := m.ctrl.Call(m, "Search", body, searchType)
+	ret0, _ := ret[0].(*externalissuesource.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockExternalSearchParserMockRecorder) Search(body, searchType interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockExternalSearchParser)(nil).Search), body, searchType)
+}
+
+// MockExternalSeriesParser is a mock of ExternalSeriesParser interface
+type MockExternalSeriesParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockExternalSeriesParserMockRecorder
+}
+
+// MockExternalSeriesParserMockRecorder is the mock recorder for MockExternalSeriesParser
+type MockExternalSeriesParserMockRecorder struct {
+	mock *MockExternalSeriesParser
+}
+
+// NewMockExternalSeriesParser creates a new mock instance
+func NewMockExternalSeriesParser(ctrl *gomock.Controller) *MockExternalSeriesParser {
+	mock := &MockExternalSeriesParser{ctrl: ctrl}
+	mock.recorder = &MockExternalSeriesParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExternalSeriesParser) EXPECT() *MockExternalSeriesParserMockRecorder {
+	return m.recorder
+}
+
+// Series mocks base method
+func (m *MockExternalSeriesParser) Series(body io.Reader) (*externalissuesource.SeriesPage, error) {
+	ret := m.ctrl.Call(m, "Series", body)
+	ret0, _ := ret[0].(*externalissuesource.SeriesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Series indicates an expected call of Series
+func (mr *MockExternalSeriesParserMockRecorder) Series(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Series", reflect.TypeOf((*MockExternalSeriesParser)(nil).Series), body)
+}
+
+// MockExternalPublisherParser is a mock of ExternalPublisherParser interface
+type MockExternalPublisherParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockExternalPublisherParserMockRecorder
+}
+
+// MockExternalPublisherParserMockRecorder is the mock recorder for MockExternalPublisherParser
+type MockExternalPublisherParserMockRecorder struct {
+	mock *MockExternalPublisherParser
+}
+
+// NewMockExternalPublisherParser creates a new mock instance
+func NewMockExternalPublisherParser(ctrl *gomock.Controller) *MockExternalPublisherParser {
+	mock := &MockExternalPublisherParser{ctrl: ctrl}
+	mock.recorder = &MockExternalPublisherParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExternalPublisherParser) EXPECT() *MockExternalPublisherParserMockRecorder {
+	return m.recorder
+}
+
+// Publisher mocks base method
+func (m *MockExternalPublisherParser) Publisher(body io.Reader) (*externalissuesource.Publisher, error) {
+	ret := m.ctrl.Call(m, "Publisher", body)
+	ret0, _ := ret[0].(*externalissuesource.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publisher indicates an expected call of Publisher
+func (mr *MockExternalPublisherParserMockRecorder) Publisher(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publisher", reflect.TypeOf((*MockExternalPublisherParser)(nil).Publisher), body)
+}
+
+// MockExternalStoryArcParser is a mock of ExternalStoryArcParser interface
+type MockExternalStoryArcParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockExternalStoryArcParserMockRecorder
+}
+
+// MockExternalStoryArcParserMockRecorder is the mock recorder for MockExternalStoryArcParser
+type MockExternalStoryArcParserMockRecorder struct {
+	mock *MockExternalStoryArcParser
+}
+
+// NewMockExternalStoryArcParser creates a new mock instance
+func NewMockExternalStoryArcParser(ctrl *gomock.Controller) *MockExternalStoryArcParser {
+	mock := &MockExternalStoryArcParser{ctrl: ctrl}
+	mock.recorder = &MockExternalStoryArcParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExternalStoryArcParser) EXPECT() *MockExternalStoryArcParserMockRecorder {
+	return m.recorder
+}
+
+// StoryArc mocks base method
+func (m *MockExternalStoryArcParser) StoryArc(body io.Reader) (*externalissuesource.StoryArc, error) {
+	ret := m.ctrl.Call(m, "StoryArc", body)
+	ret0, _ := ret[0].(*externalissuesource.StoryArc)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoryArc indicates an expected call of StoryArc
+func (mr *MockExternalStoryArcParserMockRecorder) StoryArc(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoryArc", reflect.TypeOf((*MockExternalStoryArcParser)(nil).StoryArc), body)
+}
+
 // MockExternalSourceParser is a mock of ExternalSourceParser interface
 type MockExternalSourceParser struct {
 	ctrl     *gomock.Controller
@@ -217,6 +361,58 @@ func (mr *MockExternalSourceParserMockRecorder) CharacterSearch(body interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharacterSearch", reflect.TypeOf((*MockExternalSourceParser)(nil).CharacterSearch), body)
 }
 
+// Search mocks base method
+func (m *MockExternalSourceParser) Search(body io.Reader, searchType externalissuesource.SearchType) (*externalissuesource.SearchResult, error) {
+	ret := m.ctrl.Call(m, "Search", body, searchType)
+	ret0, _ := ret[0].(*externalissuesource.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockExternalSourceParserMockRecorder) Search(body, searchType interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockExternalSourceParser)(nil).Search), body, searchType)
+}
+
+// Series mocks base method
+func (m *MockExternalSourceParser) Series(body io.Reader) (*externalissuesource.SeriesPage, error) {
+	ret := m.ctrl.Call(m, "Series", body)
+	ret0, _ := ret[0].(*externalissuesource.SeriesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Series indicates an expected call of Series
+func (mr *MockExternalSourceParserMockRecorder) Series(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Series", reflect.TypeOf((*MockExternalSourceParser)(nil).Series), body)
+}
+
+// Publisher mocks base method
+func (m *MockExternalSourceParser) Publisher(body io.Reader) (*externalissuesource.Publisher, error) {
+	ret := m.ctrl.Call(m, "Publisher", body)
+	ret0, _ := ret[0].(*externalissuesource.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publisher indicates an expected call of Publisher
+func (mr *MockExternalSourceParserMockRecorder) Publisher(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publisher", reflect.TypeOf((*MockExternalSourceParser)(nil).Publisher), body)
+}
+
+// StoryArc mocks base method
+func (m *MockExternalSourceParser) StoryArc(body io.Reader) (*externalissuesource.StoryArc, error) {
+	ret := m.ctrl.Call(m, "StoryArc", body)
+	ret0, _ := ret[0].(*externalissuesource.StoryArc)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoryArc indicates an expected call of StoryArc
+func (mr *MockExternalSourceParserMockRecorder) StoryArc(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoryArc", reflect.TypeOf((*MockExternalSourceParser)(nil).StoryArc), body)
+}
+
 // BaseUrl mocks base method
 func (m *MockExternalSourceParser) BaseUrl() string {
 	ret := m.ctrl.Call(m, "BaseUrl")
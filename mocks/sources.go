@@ -5,6 +5,7 @@
 package mock_externalissuesource
 
 import (
+	context "context"
 	externalissuesource "github.com/aimeelaplant/externalissuesource"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -71,3 +72,172 @@ func (m *MockExternalSource) SearchCharacter(query string) (externalissuesource.
 func (mr *MockExternalSourceMockRecorder) SearchCharacter(query interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharacter", reflect.TypeOf((*MockExternalSource)(nil).SearchCharacter), query)
 }
+
+// Search mocks base method
+func (m *MockExternalSource) Search(query string, searchType externalissuesource.SearchType) (externalissuesource.SearchResult, error) {
+	ret := m.ctrl.Call(m, "Search", query, searchType)
+	ret0, _ := ret[0].(externalissuesource.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockExternalSourceMockRecorder) Search(query, searchType interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockExternalSource)(nil).Search), query, searchType)
+}
+
+// Character mocks base method
+func (m *MockExternalSource) Character(url string) (*externalissuesource.Character, error) {
+	ret := m.ctrl.Call(m, "Character", url)
+	ret0, _ := ret[0].(*externalissuesource.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Character indicates an expected call of Character
+func (mr *MockExternalSourceMockRecorder) Character(url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Character", reflect.TypeOf((*MockExternalSource)(nil).Character), url)
+}
+
+// Series mocks base method
+func (m *MockExternalSource) Series(url string) (*externalissuesource.SeriesPage, error) {
+	ret := m.ctrl.Call(m, "Series", url)
+	ret0, _ := ret[0].(*externalissuesource.SeriesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Series indicates an expected call of Series
+func (mr *MockExternalSourceMockRecorder) Series(url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Series", reflect.TypeOf((*MockExternalSource)(nil).Series), url)
+}
+
+// Publisher mocks base method
+func (m *MockExternalSource) Publisher(url string) (*externalissuesource.Publisher, error) {
+	ret := m.ctrl.Call(m, "Publisher", url)
+	ret0, _ := ret[0].(*externalissuesource.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Publisher indicates an expected call of Publisher
+func (mr *MockExternalSourceMockRecorder) Publisher(url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publisher", reflect.TypeOf((*MockExternalSource)(nil).Publisher), url)
+}
+
+// StoryArc mocks base method
+func (m *MockExternalSource) StoryArc(url string) (*externalissuesource.StoryArc, error) {
+	ret := m.ctrl.Call(m, "StoryArc", url)
+	ret0, _ := ret[0].(*externalissuesource.StoryArc)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoryArc indicates an expected call of StoryArc
+func (mr *MockExternalSourceMockRecorder) StoryArc(url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoryArc", reflect.TypeOf((*MockExternalSource)(nil).StoryArc), url)
+}
+
+// IssueContext mocks base method
+func (m *MockExternalSource) IssueContext(ctx context.Context, url string) (*externalissuesource.Issue, error) {
+	ret := m.ctrl.Call(m, "IssueContext", ctx, url)
+	ret0, _ := ret[0].(*externalissuesource.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueContext indicates an expected call of IssueContext
+func (mr *MockExternalSourceMockRecorder) IssueContext(ctx, url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueContext", reflect.TypeOf((*MockExternalSource)(nil).IssueContext), ctx, url)
+}
+
+// CharacterPageContext mocks base method
+func (m *MockExternalSource) CharacterPageContext(ctx context.Context, url string) (*externalissuesource.CharacterPage, error) {
+	ret := m.ctrl.Call(m, "CharacterPageContext", ctx, url)
+	ret0, _ := ret[0].(*externalissuesource.CharacterPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CharacterPageContext indicates an expected call of CharacterPageContext
+func (mr *MockExternalSourceMockRecorder) CharacterPageContext(ctx, url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharacterPageContext", reflect.TypeOf((*MockExternalSource)(nil).CharacterPageContext), ctx, url)
+}
+
+// SearchCharacterContext mocks base method
+func (m *MockExternalSource) SearchCharacterContext(ctx context.Context, query string) (externalissuesource.CharacterSearchResult, error) {
+	ret := m.ctrl.Call(m, "SearchCharacterContext", ctx, query)
+	ret0, _ := ret[0].(externalissuesource.CharacterSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCharacterContext indicates an expected call of SearchCharacterContext
+func (mr *MockExternalSourceMockRecorder) SearchCharacterContext(ctx, query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharacterContext", reflect.TypeOf((*MockExternalSource)(nil).SearchCharacterContext), ctx, query)
+}
+
+// SearchContext mocks base method
+func (m *MockExternalSource) SearchContext(ctx context.Context, query string, searchType externalissuesource.SearchType) (externalissuesource.SearchResult, error) {
+	ret := m.ctrl.Call(m, "SearchContext", ctx, query, searchType)
+	ret0, _ := ret[0].(externalissuesource.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchContext indicates an expected call of SearchContext
+func (mr *MockExternalSourceMockRecorder) SearchContext(ctx, query, searchType interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchContext", reflect.TypeOf((*MockExternalSource)(nil).SearchContext), ctx, query, searchType)
+}
+
+// CharacterContext mocks base method
+func (m *MockExternalSource) CharacterContext(ctx context.Context, url string) (*externalissuesource.Character, error) {
+	ret := m.ctrl.Call(m, "CharacterContext", ctx, url)
+	ret0, _ := ret[0].(*externalissuesource.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CharacterContext indicates an expected call of CharacterContext
+func (mr *MockExternalSourceMockRecorder) CharacterContext(ctx, url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharacterContext", reflect.TypeOf((*MockExternalSource)(nil).CharacterContext), ctx, url)
+}
+
+// SeriesContext mocks base method
+func (m *MockExternalSource) SeriesContext(ctx context.Context, url string) (*externalissuesource.SeriesPage, error) {
+	ret := m.ctrl.Call(m, "SeriesContext", ctx, url)
+	ret0, _ := ret[0].(*externalissuesource.SeriesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeriesContext indicates an expected call of SeriesContext
+func (mr *MockExternalSourceMockRecorder) SeriesContext(ctx, url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesContext", reflect.TypeOf((*MockExternalSource)(nil).SeriesContext), ctx, url)
+}
+
+// PublisherContext mocks base method
+func (m *MockExternalSource) PublisherContext(ctx context.Context, url string) (*externalissuesource.Publisher, error) {
+	ret := m.ctrl.Call(m, "PublisherContext", ctx, url)
+	ret0, _ := ret[0].(*externalissuesource.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublisherContext indicates an expected call of PublisherContext
+func (mr *MockExternalSourceMockRecorder) PublisherContext(ctx, url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublisherContext", reflect.TypeOf((*MockExternalSource)(nil).PublisherContext), ctx, url)
+}
+
+// StoryArcContext mocks base method
+func (m *MockExternalSource) StoryArcContext(ctx context.Context, url string) (*externalissuesource.StoryArc, error) {
+	ret := m.ctrl.Call(m, "StoryArcContext", ctx, url)
+	ret0, _ := ret[0].(*externalissuesource.StoryArc)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoryArcContext indicates an expected call of StoryArcContext
+func (mr *MockExternalSourceMockRecorder) StoryArcContext(ctx, url interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoryArcContext", reflect.TypeOf((*MockExternalSource)(nil).StoryArcContext), ctx, url)
+}
@@ -26,6 +26,17 @@ const (
 	Other
 )
 
+var formatNames = [...]string{"Unknown", "Standard", "TPB", "Manga", "HC", "OGN", "Web", "Anthology", "Bookshelf",
+	"Magazine", "DigitalMedia", "MiniComic", "Prestige", "Ashcan", "Flipbook", "Fanzine", "Other"}
+
+// Gets the name of the format, such as `TPB`.
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return formatNames[Unknown]
+	}
+	return formatNames[f]
+}
+
 // A transformed object from a remote source with all the issues attached.
 type Character struct {
 	Publisher string
@@ -43,6 +54,7 @@ func (c *Character) AddIssue(issue Issue) []Issue {
 type Issue struct {
 	Series          string
 	Vendor          string	  // The publisher of the issue.
+	VendorId        string    // unique identifier for the publisher of the issue, which stays the same when the publisher is renamed.
 	Id              string    // unique identifier for the issue.
 	Number          string    // The number of the issue - for example, Astonishing X-Men 1 with `1` being the issue number.
 	Format          Format    // The type of issue.
@@ -52,6 +64,22 @@ type Issue struct {
 	SeriesId        string    // unique identifier for the series/title of the issue.
 	MonthUncertain  bool 	  // Sometimes an external source has the date of "annual", so in this case the month is uncertain.
 	IsReprint       bool     // The issue is a full reprint with no original story.
+	CoverDate       CoverDate // The cover date with its original text and precision. `PublicationDate` falls within it, in the last month for a dual-month cover.
+	OnSaleEstimated bool      // Whether `OnSaleDate` was estimated from the cover date instead of observed by the source.
+	Credits         []Credit  // The creators of the issue and of each of its stories.
+	Characters      []CharacterLink // The characters that appear in the issue or any of its stories, each listed once.
+	StoryArcs       []StoryArcLink  // The story arcs the issue or any of its stories are part of, each listed once.
+	OriginalIssueId string    // unique identifier for the issue this one is a variant, printing or other version of. Empty for original issues.
+	VersionOf       *IssueLink  // The issue this one is a version of. Nil for original issues.
+	Versions        []IssueLink // The variants, printings and other versions that point to this issue.
+	VariantInfo     VariantInfo // What kind of variant, printing or edition the issue is.
+	Covers          []CoverImage // The front cover and the thumbnails of the variant covers.
+	CoverPrice      *Price    // The price printed on the cover. Nil if the source doesn't have it.
+	PageCount       int       // The number of pages, or 0 if the source doesn't have it.
+	UPC             string    // The UPC barcode with only its digits, including the 5-digit supplement if the source has it.
+	ISBN            string    // The ISBN-10 or ISBN-13 with only its digits and `X` check digit.
+	Rating          Rating    // The publisher's age rating.
+	Stories         []Story   // The stories in the issue in the order they're printed.
 }
 
 // Represents a character's detailed paged.
@@ -64,12 +92,88 @@ type CharacterPage struct {
 	OtherIdentities []CharacterLink // Links to other identities for the character.
 }
 
-// A link to a character with its URL and name from the search results.
-type CharacterLink struct {
+// Represents a series' (or title's) page with the links to all of its issues.
+type SeriesPage struct {
+	Id         string
+	Name       string            // The name of the series without its year, such as `Astonishing X-Men`.
+	StartYear  int               // The year the series began.
+	Publisher  string            // The name of the publisher.
+	Volume     string            // The volume of the series if the source has it.
+	Format     Format            // The format of the series' issues.
+	IssueLinks []SeriesIssueLink // Links to the series' issues in the order they're listed, with the variants after their issue.
+}
+
+// A link to an issue from a series page.
+type SeriesIssueLink struct {
+	Url         string
+	Number      string // The number of the issue, such as `22` or `Annual 1`.
+	IsVariant   bool   // Whether it's a variant, 2nd printing, etc. of another issue in the series.
+	VariantName string // The name of the variant, such as `Wolverine Cover` or `(2nd Printing)`.
+}
+
+// Represents a publisher's page with its imprints and the links to all of its series.
+type Publisher struct {
+	Id          string
+	Name        string
+	Parent      *PublisherLink  // The publisher this one is an imprint of, such as Marvel for Epic Comics. Nil if it isn't an imprint.
+	Imprints    []PublisherLink // The publisher's imprints.
+	YearBegan   int             // The first year the publisher was active.
+	YearEnded   int             // The last year the publisher was active, or 0 if it's still active.
+	SeriesLinks []SeriesLink    // Links to the publisher's series.
+}
+
+// A link to a publisher with its URL and name.
+type PublisherLink struct {
 	Url  string
 	Name string
 }
 
+// A link to a series from a publisher's page.
+type SeriesLink struct {
+	Url       string
+	Name      string // The name of the series without its year, such as `Astonishing X-Men`.
+	StartYear int    // The year the series began.
+	Publisher string // The publisher's name. Only set for search results that show it.
+}
+
+// Represents a story arc's page with the links to its issues in reading order.
+type StoryArc struct {
+	Id         string
+	Name       string
+	IssueLinks []StoryArcIssueLink // Links to the arc's issues in the order they're read.
+}
+
+// A link to an issue from a story arc's page.
+type StoryArcIssueLink struct {
+	Url    string
+	Series string // The name of the series with its year, such as `Astonishing X-Men (2004)`, like `Issue.Series`.
+	Number string // The number of the issue, such as `22`.
+}
+
+// A link to an issue with its URL, ID and title.
+type IssueLink struct {
+	Url   string
+	Id    string
+	Title string // The issue as the source shows it, such as `Detective Comics (2011) #27 Burnham Variant`.
+}
+
+// A link to a story arc with its URL and name.
+type StoryArcLink struct {
+	Url       string
+	Name      string
+	Publisher string // The publisher's name. Only set for search results that show it.
+}
+
+// A link to a character with its URL and name from the search results.
+type CharacterLink struct {
+	Url            string
+	Name           string         // The name as the source shows it, such as `Cyclops (Marvel)(03 - Scott Summers)`.
+	Appearance     AppearanceHint // What the issue page says about the character's appearance. Only set for `Issue.Characters`.
+	Id             string         // unique identifier for the character. Only set for search results.
+	Publisher      string         // The publisher's name, such as `Marvel`. Only set for search results that show it.
+	Disambiguation string         // The universe or version that tells characters with the same name apart, such as `03 - Scott Summers`. Only set for search results.
+}
+
 // Represents the search results returned for querying a character's name.
 type CharacterSearchResult struct {
 	Results []CharacterLink
@@ -0,0 +1,114 @@
+package externalissuesource
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// The estimator used when a parser or source isn't given one. It estimates US monthlies as 2 months before
+// the cover month, or 3 months before the last month for dual-month covers, modern books from `ModernOnSaleYear`
+// on as their cover month, or the first month of a dual-month cover, and UK weeklies as their cover date.
+// Register custom rules on it to change the estimates everywhere.
+var DefaultOnSaleEstimator = NewOnSaleRules(
+	CoverOffsetEstimator{Months: 2, DualMonths: 3},
+	OnSaleRule{FromYear: ModernOnSaleYear, Estimator: CoverOffsetEstimator{DualMonths: 1}},
+	OnSaleRule{Publisher: "IPC", Estimator: CoverOffsetEstimator{}},
+	OnSaleRule{Publisher: "Fleetway", Estimator: CoverOffsetEstimator{}},
+	OnSaleRule{Publisher: "Rebellion", Estimator: CoverOffsetEstimator{}},
+	OnSaleRule{Publisher: "D.C. Thomson", Estimator: CoverOffsetEstimator{}},
+	OnSaleRule{Publisher: "DC Thomson", Estimator: CoverOffsetEstimator{}},
+)
+
+// The first cover year that `DefaultOnSaleEstimator` treats as modern, when the cover month is about the month
+// the issue went on sale rather than a couple of months after it.
+const ModernOnSaleYear = 2015
+
+// Estimates when an issue went on sale when the source only has its cover date.
+type OnSaleEstimator interface {
+	EstimateOnSale(issue *Issue) time.Time
+}
+
+// Estimates the on-sale date as a number of months before the cover date.
+// Covers that only have a season or a year can't be narrowed down to a month, so they go on sale on the publication date.
+type CoverOffsetEstimator struct {
+	Months     int // The months before a cover month or day.
+	DualMonths int // The months before the last month of a dual-month cover, such as Jan/Feb.
+}
+
+func (e CoverOffsetEstimator) EstimateOnSale(issue *Issue) time.Time {
+	switch issue.CoverDate.Precision {
+	case PrecisionDay, PrecisionMonth:
+		return issue.PublicationDate.AddDate(0, -e.Months, 0)
+	case PrecisionDualMonth:
+		return issue.PublicationDate.AddDate(0, -e.DualMonths, 0)
+	}
+	return issue.PublicationDate
+}
+
+// Estimates the on-sale date with the estimator for the issues from a publisher in a range of cover years.
+type OnSaleRule struct {
+	Publisher string          // Matches publishers whose names start with this, ignoring case. Matches every publisher if empty.
+	FromYear  int             // The first cover year the rule applies to. No lower bound if 0.
+	ToYear    int             // The last cover year the rule applies to. No upper bound if 0.
+	Estimator OnSaleEstimator // How the matching issues are estimated.
+}
+
+// Whether the rule applies to the issue.
+func (r *OnSaleRule) matches(issue *Issue) bool {
+	year := issue.PublicationDate.Year()
+	if r.FromYear != 0 && year < r.FromYear {
+		return false
+	}
+	if r.ToYear != 0 && year > r.ToYear {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(issue.Vendor), strings.ToLower(r.Publisher))
+}
+
+// Picks an estimator for each issue by its publisher and cover year. Rules registered later take precedence,
+// so custom rules override the built-in ones. Issues that don't match any rule use the fallback.
+// It's safe to register rules while issues are being estimated.
+type OnSaleRules struct {
+	mu       sync.RWMutex
+	rules    []OnSaleRule
+	fallback OnSaleEstimator
+}
+
+// Adds a rule that takes precedence over the rules registered before it.
+func (r *OnSaleRules) Register(rule OnSaleRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rule)
+}
+
+func (r *OnSaleRules) EstimateOnSale(issue *Issue) time.Time {
+	r.mu.RLock()
+	estimator := r.fallback
+	for i := len(r.rules) - 1; i >= 0; i-- {
+		if r.rules[i].matches(issue) {
+			estimator = r.rules[i].Estimator
+			break
+		}
+	}
+	r.mu.RUnlock()
+	return estimator.EstimateOnSale(issue)
+}
+
+// Creates the rules with the fallback for issues that don't match any of them. Later rules take precedence.
+func NewOnSaleRules(fallback OnSaleEstimator, rules ...OnSaleRule) *OnSaleRules {
+	return &OnSaleRules{rules: rules, fallback: fallback}
+}
+
+// Sets the issue's on-sale date with the estimator, or `DefaultOnSaleEstimator` if it's nil, and flags it as estimated.
+// Issues without a publication date are left alone.
+func estimateOnSale(estimator OnSaleEstimator, issue *Issue) {
+	if issue.PublicationDate.IsZero() {
+		return
+	}
+	if estimator == nil {
+		estimator = DefaultOnSaleEstimator
+	}
+	issue.OnSaleDate = estimator.EstimateOnSale(issue)
+	issue.OnSaleEstimated = true
+}
@@ -0,0 +1,71 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func coverIssue(vendor string, coverDate string, publicationDate time.Time) *Issue {
+	return &Issue{Vendor: vendor, CoverDate: ParseCoverDate(coverDate), PublicationDate: publicationDate}
+}
+
+func TestCoverOffsetEstimator(t *testing.T) {
+	estimator := CoverOffsetEstimator{Months: 2, DualMonths: 3}
+	assert.Equal(t, date(2007, time.August, 1), estimator.EstimateOnSale(coverIssue("Marvel", "October 2007", date(2007, time.October, 1))))
+	assert.Equal(t, date(1971, time.June, 1), estimator.EstimateOnSale(coverIssue("Marvel", "Jul/Sep 1971", date(1971, time.September, 1))))
+	assert.Equal(t, date(1985, time.January, 1), estimator.EstimateOnSale(coverIssue("DC", "Summer 1985", date(1985, time.January, 1))))
+	assert.Equal(t, date(2014, time.January, 1), estimator.EstimateOnSale(coverIssue("DC", "2014", date(2014, time.January, 1))))
+}
+
+func TestOnSaleRules(t *testing.T) {
+	rules := NewOnSaleRules(
+		CoverOffsetEstimator{Months: 2},
+		OnSaleRule{Publisher: "Marvel", FromYear: 2015, Estimator: CoverOffsetEstimator{Months: 1}},
+		OnSaleRule{Publisher: "Rebellion", Estimator: CoverOffsetEstimator{}},
+	)
+	assert.Equal(t, date(2016, time.May, 1), rules.EstimateOnSale(coverIssue("Marvel", "June 2016", date(2016, time.June, 1))))
+	assert.Equal(t, date(2016, time.April, 1), rules.EstimateOnSale(coverIssue("DC", "June 2016", date(2016, time.June, 1))))
+	assert.Equal(t, date(2007, time.August, 1), rules.EstimateOnSale(coverIssue("Marvel", "October 2007", date(2007, time.October, 1))))
+	assert.Equal(t, date(2016, time.June, 1), rules.EstimateOnSale(coverIssue("rebellion developments", "June 2016", date(2016, time.June, 1))))
+
+	// Rules registered later win.
+	rules.Register(OnSaleRule{Publisher: "Marvel", FromYear: 2016, ToYear: 2016, Estimator: CoverOffsetEstimator{Months: 3}})
+	assert.Equal(t, date(2016, time.March, 1), rules.EstimateOnSale(coverIssue("Marvel", "June 2016", date(2016, time.June, 1))))
+	assert.Equal(t, date(2017, time.May, 1), rules.EstimateOnSale(coverIssue("Marvel", "June 2017", date(2017, time.June, 1))))
+	rules.Register(OnSaleRule{Estimator: CoverOffsetEstimator{Months: 4}})
+	assert.Equal(t, date(2016, time.February, 1), rules.EstimateOnSale(coverIssue("DC", "June 2016", date(2016, time.June, 1))))
+}
+
+func TestDefaultOnSaleEstimator(t *testing.T) {
+	assert.Equal(t, date(2007, time.August, 1), DefaultOnSaleEstimator.EstimateOnSale(coverIssue("Marvel", "October 2007", date(2007, time.October, 1))))
+	assert.Equal(t, date(1971, time.June, 1), DefaultOnSaleEstimator.EstimateOnSale(coverIssue("Marvel", "Jul/Sep 1971", date(1971, time.September, 1))))
+	// Modern books go on sale in their cover month.
+	assert.Equal(t, date(2016, time.June, 1), DefaultOnSaleEstimator.EstimateOnSale(coverIssue("Marvel", "June 2016", date(2016, time.June, 1))))
+	assert.Equal(t, date(2016, time.January, 1), DefaultOnSaleEstimator.EstimateOnSale(coverIssue("DC Comics", "Jan/Feb 2016", date(2016, time.February, 1))))
+	assert.Equal(t, date(2016, time.June, 8), DefaultOnSaleEstimator.EstimateOnSale(coverIssue("Rebellion", "June 8 2016", date(2016, time.June, 8))))
+}
+
+func TestCbParser_Issue_OnSaleModern(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue_there_are.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "October 2015", issue.CoverDate.Text)
+	assert.True(t, issue.OnSaleEstimated)
+	assert.Equal(t, date(2015, time.October, 1), issue.OnSaleDate)
+}
+
+func TestCbParser_Issue_OnSaleEstimator(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{onSale: CoverOffsetEstimator{Months: 1}}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.True(t, issue.OnSaleEstimated)
+	assert.Equal(t, date(2007, time.September, 1), issue.OnSaleDate)
+}
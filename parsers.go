@@ -6,11 +6,12 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
-	"log"
 	"golang.org/x/text/encoding/charmap"
 	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
 )
 
 const (
@@ -22,6 +23,7 @@ var (
 	// ErrRecordNotFound record not found error, happens when haven't find any matched data when looking up with a struct
 	ErrConnection = errors.New("page returned connection issue")
 	ErrParse        = errors.New("can't parse the page")
+	ErrUnsupported  = errors.New("the source doesn't have this kind of page")
 	cdDatePrefixMap  = map[string]bool {
 		"Mid": true,
 		"Early": true,
@@ -57,18 +59,14 @@ var (
 		"Sep/Nov": true,
 		"Dec/Feb": true, // keep year
 	}
-	keepYears = map[string]bool{
-		"Jan/Feb": true,
-		"Dec/Jan": true,
-		"Feb/Mar": true,
-		"Jan/Mar": true,
-		"Nov/Jan": true,
-		"Dec/Feb": true,
-	}
 	regMY = regexp.MustCompile(fmt.Sprintf(`^%s \d{4}$`, regMonths))
 	regMDY = regexp.MustCompile(fmt.Sprintf(`^%s \d{1,2} \d{4}$`, regMonths))
 	regmY = regexp.MustCompile(`^\w{3} \d{4}$`)
 	regY = regexp.MustCompile(`^(\d{4})$`)
+	regSeriesYear = regexp.MustCompile(`^(.+) \((\d{4})\)$`)
+	regSeriesIssue = regexp.MustCompile(`^#(\S+)\s*(.*)$`)
+	regStoryArcIssue = regexp.MustCompile(`^(.+) #(\S+)$`)
+	regPublisherYears = regexp.MustCompile(`(\d{4})\s*-\s*(\d{4}|Present)?`)
 	cbIssueFormats = map[Format]string{
 		Standard: "Standard Comic Issue",
 		TPB: "Trade Paperback",
@@ -105,17 +103,39 @@ type ExternalCharacterSearchParser interface {
 	CharacterSearch(body io.Reader) (*CharacterSearchResult, error)
 }
 
+type ExternalSearchParser interface {
+	Search(body io.Reader, searchType SearchType) (*SearchResult, error)
+}
+
+type ExternalSeriesParser interface {
+	Series(body io.Reader) (*SeriesPage, error)
+}
+
+type ExternalPublisherParser interface {
+	Publisher(body io.Reader) (*Publisher, error)
+}
+
+type ExternalStoryArcParser interface {
+	StoryArc(body io.Reader) (*StoryArc, error)
+}
+
 // An interface that defines parsing entities from a remote external source.
 type ExternalSourceParser interface {
 	ExternalIssueParser
 	ExternalCharacterParser
 	ExternalCharacterSearchParser
+	ExternalSearchParser
+	ExternalSeriesParser
+	ExternalPublisherParser
+	ExternalStoryArcParser
 	BaseUrl() string
 }
 
 // This struct implements parsing entities from the cb source.
 type CbParser struct {
-	baseUrl string // The base URL for constructing links. Default is http://comicbookdb.com if not provided.
+	baseUrl string          // The base URL for constructing links. Default is http://comicbookdb.com if not provided.
+	onSale  OnSaleEstimator // Estimates the on-sale dates. Default is `DefaultOnSaleEstimator` if not provided.
+	logger  Logger          // Logs the warnings from `Issue`. Nothing is logged if not provided.
 }
 
 // Parses a character's page and returns the corresponding struct.
@@ -124,10 +144,10 @@ func (p *CbParser) Character(body io.Reader) (*CharacterPage, error) {
 	// Create new decoder every time to make this method concurrent safe.
 	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
 	if err != nil {
-		return nil, ErrParse
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
 	}
 	if strings.Contains(doc.Text(), "mysql_connect()") {
-		return nil, ErrConnection
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
 	}
 	// Get the name, publisher, and title of page.
 	selection := doc.Find(".page_headline").Not("").First()
@@ -199,48 +219,51 @@ func (p *CbParser) BaseUrl() string {
 	return p.baseUrl
 }
 
-// Parses the links to character profiles and their names from the search page.
+// Parses the links to character profiles and their names from the search page,
+// with each character's ID, publisher and disambiguation from its name.
 func (p *CbParser) CharacterSearch(body io.Reader) (*CharacterSearchResult, error) {
-	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
+	searchResult, err := p.Search(body, SearchCharacter)
 	if err != nil {
-		return nil, ErrParse
+		return nil, err
 	}
-	if strings.Contains(doc.Text(), "mysql_connect()") {
-		return nil, ErrConnection
-	}
-	characterSearchResult := new(CharacterSearchResult)
-	characterLinks := make([]CharacterLink, 0)
-
-	doc.FindMatcher(cascadia.MustCompile("td[width=\"850\"]")).Find("a").Each(func(i int, s *goquery.Selection) {
-		hrefValue, exists := s.Attr("href")
-		if exists && strings.HasPrefix(hrefValue, "character.php") {
-			characterLink := CharacterLink{Name: strings.TrimSpace(s.Text()), Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue)}
-			characterLinks = append(characterLinks, characterLink)
-		}
-	})
-	characterSearchResult.Results = characterLinks
-	return characterSearchResult, nil
+	return &CharacterSearchResult{Results: searchResult.Characters}, nil
 }
 
-// Parses an issue page and returns the corresponding struct.
+// Parses an issue page and returns the corresponding struct. Use `IssueResult` to get the warnings for the
+// fields that couldn't be parsed, otherwise they're logged with the parser's logger.
 func (p *CbParser) Issue(body io.Reader) (*Issue, error) {
+	result, err := p.IssueResult(body)
+	if err != nil {
+		return nil, err
+	}
+	logWarnings(p.logger, result.Warnings)
+	return result.Issue, nil
+}
+
+// Parses an issue page and returns the corresponding struct with the warnings for the fields that couldn't be parsed.
+func (p *CbParser) IssueResult(body io.Reader) (*IssueResult, error) {
 	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
 	if err != nil {
-		return nil, ErrParse
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
 	}
 	if strings.Contains(doc.Text(), "mysql_connect()") {
-		return nil, ErrConnection
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
 	}
 	issue := new(Issue)
+	warnings := make([]ParseWarning, 0)
 
 	doc.FindMatcher(cascadia.MustCompile("body > table > tbody > tr:nth-child(2) > td:nth-child(3) > table > tbody > tr")).FindMatcher(cascadia.MustCompile("a, strong, span")).Each(func(i int, s *goquery.Selection) {
 		hrefValue, ex := s.Attr("href")
 		if issue.Vendor == "" && ex && strings.HasPrefix(hrefValue, "publisher.php"){
 			issue.Vendor = strings.TrimSpace(s.Text())
+			if equalIndex := strings.Index(hrefValue, "="); equalIndex != -1 {
+				issue.VendorId = hrefValue[equalIndex+1:]
+			}
 		}
 		if issue.PublicationDate.Year() <= 1 && ex && strings.HasPrefix(hrefValue, "coverdate.php") {
 			dualDate := false
 			dateText := strings.TrimSpace(s.Text())
+			issue.CoverDate = ParseCoverDate(dateText)
 			var trimmedDateText string
 			spaceIndex := strings.Index(dateText, " ")
 			if spaceIndex != -1 && cdDatePrefixMap[dateText[:spaceIndex]] {
@@ -267,30 +290,17 @@ func (p *CbParser) Issue(body io.Reader) (*Issue, error) {
 			}
 			pubDate, err := time.Parse(format, trimmedDateText)
 			if err != nil {
-				// fail silently but log it.
-				log.Println(fmt.Sprintf("ERROR: %s", err))
+				// Keep parsing the rest of the issue, but let the caller know the date is missing.
+				warnings = append(warnings, ParseWarning{Field: "cover date", Text: dateText, Reason: err.Error()})
 			}
 			issue.PublicationDate = pubDate
-			if format != "2006" {
-				// Determine the on sale date was 2 months ago.
-				if !dualDate {
-					issue.OnSaleDate = pubDate.AddDate(0, -2, 0)
-				} else {
-					// If it's a dual date, we wanna go 3 months back from the latest month.
-					// Check if we should keep the year for the issue.
-					if keepYears[dateText[0:7]] {
-						// Corrects the issues that fall within a new year, such as Dec/Jan 1971. CBDB lists the year for December and not
-						// the year for January. So we want January 1972 as the publication date and December 1971 as the sale date.
-						issue.PublicationDate = pubDate.AddDate(1, 0, 0)
-						issue.OnSaleDate = issue.PublicationDate.AddDate(0, -3, 0)
-					} else {
-						issue.PublicationDate = pubDate
-						issue.OnSaleDate = pubDate.AddDate(0, -3, 0)
-					}
-				}
-			} else {
-				issue.OnSaleDate = issue.PublicationDate
+			if format == "2006" {
 				issue.MonthUncertain = true
+			} else if dualDate && issue.CoverDate.Precision == PrecisionDualMonth {
+				// The publication date is the last month of the cover date. CBDB lists the year for the first month, so only
+				// the issues whose months wrap into a new year, such as Dec/Jan 1971, are in the next year: January 1972.
+				latest := issue.CoverDate.Latest
+				issue.PublicationDate = time.Date(latest.Year(), latest.Month(), 1, 0, 0, 0, 0, time.UTC)
 			}
 		}
 		// Sometimes they lock cloning of the issue or editing the issue, so check the issue_history.php
@@ -309,8 +319,8 @@ func (p *CbParser) Issue(body io.Reader) (*Issue, error) {
 		}
 		classValue, ex := s.Attr("class")
 		if ex && classValue == "page_subheadline test" {
-			r := regexp.MustCompile("(Cover [A-Za-z])|(\\(2nd Printing\\))|(Variant)")
-			issue.IsVariant = r.MatchString(strings.TrimSpace(s.Text()))
+			issue.VariantInfo = ParseVariantInfo(strings.TrimSpace(s.Text()))
+			issue.IsVariant = issue.VariantInfo.IsVariant()
 		}
 		if issue.Number == "" && ex && classValue == "page_headline" {
 			// Get the issue number.
@@ -329,6 +339,7 @@ func (p *CbParser) Issue(body io.Reader) (*Issue, error) {
 	})
 
 	foundFormat := false
+	unknownFormatText := ""
 	doc.FindMatcher(cascadia.MustCompile("td[width=\"850\"]")).FindMatcher(cascadia.MustCompile("td[colspan=\"3\"]")).Each(func(i int, s *goquery.Selection) {
 		trimmedText := strings.TrimSpace(s.Text())
 		formatIndex := strings.Index(trimmedText, "Format:")
@@ -353,22 +364,238 @@ func (p *CbParser) Issue(body io.Reader) (*Issue, error) {
 					break
 				}
 			}
+			if !foundFormat {
+				unknownFormatText = strings.TrimSpace(formatText)
+			}
 		}
 	})
+	// The cover price, format and barcodes are listed with labels in the same block.
+	doc.Find("td[width=\"850\"] td[colspan=\"3\"]").Each(func(i int, s *goquery.Selection) {
+		cbProductDetails(issue, cbLabeledValues(s))
+	})
 	if !foundFormat {
 		issue.Format = Unknown
+		if unknownFormatText != "" {
+			warnings = append(warnings, ParseWarning{Field: "format", Text: unknownFormatText, Reason: "unknown format"})
+		}
+	}
+	// CBDB only has the cover date, so the on-sale date is always estimated.
+	estimateOnSale(p.onSale, issue)
+
+	// The creators for the whole issue are in the box next to the cover, and each story lists its own creators.
+	issue.Credits = cbCredits(doc.Find("td[width=\"366\"]").First(), WholeIssue)
+	issue.Stories = make([]Story, 0)
+	doc.Find(".page_subheadline").Each(func(i int, s *goquery.Selection) {
+		if strings.Contains(s.Text(), "Stories") {
+			issue.Credits = append(issue.Credits, cbCredits(s.Closest("table.noHeaderBox"), WholeIssue)...)
+			issue.Stories = p.stories(s.Closest("table.noHeaderBox"), issue.Credits)
+		}
+	})
+	issue.Characters = p.characters(doc.Find("td[width=\"850\"]").First())
+	issue.VersionOf, issue.Versions = p.versions(doc.Find("td[width=\"850\"]").First())
+	if issue.VersionOf != nil {
+		issue.OriginalIssueId = issue.VersionOf.Id
+	}
+	issue.Covers = p.covers(doc)
+	issue.StoryArcs = make([]StoryArcLink, 0)
+	storyArcUrls := make(map[string]bool)
+	// The issue's arcs are listed first and then each story's arcs, which are usually the same ones.
+	doc.Find("td[width=\"850\"] a[href^=\"storyarc.php?ID=\"]").Each(func(i int, s *goquery.Selection) {
+		storyArcLink := StoryArcLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), s.AttrOr("href", "")), Name: strings.TrimSpace(s.Text())}
+		if !storyArcUrls[storyArcLink.Url] {
+			storyArcUrls[storyArcLink.Url] = true
+			issue.StoryArcs = append(issue.StoryArcs, storyArcLink)
+		}
+	})
+
+	return &IssueResult{Issue: issue, Warnings: warnings}, nil
+}
+
+// Parses a series' (title's) page and returns the corresponding struct.
+// Issues listed with more than their number, such as `#22 Wolverine Cover`, are variants.
+func (p *CbParser) Series(body io.Reader) (*SeriesPage, error) {
+	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	if strings.Contains(doc.Text(), "mysql_connect()") {
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
+	}
+	content := doc.Find("td[width=\"850\"]").First()
+	headline := strings.TrimSpace(content.Find(".page_headline").First().Text())
+	if headline == "" {
+		return nil, &ParseError{Field: "headline", Cause: ErrParse}
+	}
+	seriesPage := new(SeriesPage)
+	seriesPage.Name = headline
+	if match := regSeriesYear.FindStringSubmatch(headline); match != nil {
+		seriesPage.Name = match[1]
+		seriesPage.StartYear, _ = strconv.Atoi(match[2])
+	}
+	seriesPage.Publisher = strings.TrimSpace(content.Find("a[href^=\"publisher.php\"]").First().Text())
+
+	content.Find("strong").Each(func(i int, s *goquery.Selection) {
+		// The value is the text right after the label, such as `<strong>Volume:</strong> 3<br>`.
+		value := ""
+		if next := s.Nodes[0].NextSibling; next != nil && next.Type == html.TextNode {
+			value = strings.TrimSpace(next.Data)
+		}
+		switch strings.TrimSpace(s.Text()) {
+		case "Volume:":
+			seriesPage.Volume = value
+		case "Format:":
+			for format, text := range cbIssueFormats {
+				if strings.Contains(value, text) {
+					seriesPage.Format = format
+					break
+				}
+			}
+		}
+	})
+
+	issueLinks := make([]SeriesIssueLink, 0)
+	content.Find("a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if !ex {
+			return
+		}
+		if seriesPage.Id == "" && strings.HasPrefix(hrefValue, "title_history.php") {
+			if equalIndex := strings.Index(hrefValue, "="); equalIndex != -1 {
+				seriesPage.Id = hrefValue[equalIndex+1:]
+			}
+		}
+		text := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(hrefValue, "issue.php?ID=") || text == "" {
+			return
+		}
+		issueLink := SeriesIssueLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue), Number: text}
+		if match := regSeriesIssue.FindStringSubmatch(text); match != nil {
+			issueLink.Number = match[1]
+			issueLink.VariantName = match[2]
+			issueLink.IsVariant = match[2] != ""
+		}
+		issueLinks = append(issueLinks, issueLink)
+	})
+	seriesPage.IssueLinks = issueLinks
+	return seriesPage, nil
+}
+
+// Parses a publisher's page and returns the corresponding struct.
+func (p *CbParser) Publisher(body io.Reader) (*Publisher, error) {
+	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	if strings.Contains(doc.Text(), "mysql_connect()") {
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
+	}
+	content := doc.Find("td[width=\"850\"]").First()
+	publisher := new(Publisher)
+	publisher.Name = strings.TrimSpace(content.Find(".page_headline").First().Text())
+	if publisher.Name == "" {
+		return nil, &ParseError{Field: "headline", Cause: ErrParse}
+	}
+
+	content.Find("strong").Each(func(i int, s *goquery.Selection) {
+		switch strings.TrimSpace(s.Text()) {
+		case "Parent Publisher:":
+			parent := s.NextFiltered("a[href^=\"publisher.php\"]")
+			if hrefValue, ex := parent.Attr("href"); ex {
+				publisher.Parent = &PublisherLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue), Name: strings.TrimSpace(parent.Text())}
+			}
+		case "Years Active:":
+			if next := s.Nodes[0].NextSibling; next != nil && next.Type == html.TextNode {
+				if match := regPublisherYears.FindStringSubmatch(next.Data); match != nil {
+					publisher.YearBegan, _ = strconv.Atoi(match[1])
+					// The year is `Present` for the publishers that are still active.
+					publisher.YearEnded, _ = strconv.Atoi(match[2])
+				}
+			}
+		}
+	})
+
+	imprints := make([]PublisherLink, 0)
+	seriesLinks := make([]SeriesLink, 0)
+	content.Find("table.noHeaderBox").Each(func(i int, section *goquery.Selection) {
+		heading := strings.TrimSpace(section.Find(".page_subheadline").First().Text())
+		section.Find("a").Each(func(i int, s *goquery.Selection) {
+			hrefValue, ex := s.Attr("href")
+			text := strings.TrimSpace(s.Text())
+			if !ex || text == "" {
+				return
+			}
+			switch {
+			case strings.HasPrefix(heading, "Imprints") && strings.HasPrefix(hrefValue, "publisher.php?ID="):
+				imprints = append(imprints, PublisherLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue), Name: text})
+			case strings.HasPrefix(heading, "Titles") && strings.HasPrefix(hrefValue, "title.php?ID="):
+				seriesLink := SeriesLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue), Name: text}
+				if match := regSeriesYear.FindStringSubmatch(text); match != nil {
+					seriesLink.Name = match[1]
+					seriesLink.StartYear, _ = strconv.Atoi(match[2])
+				}
+				seriesLinks = append(seriesLinks, seriesLink)
+			}
+		})
+	})
+	if hrefValue, ex := content.Find("a[href^=\"publisher_history.php\"]").First().Attr("href"); ex {
+		if equalIndex := strings.Index(hrefValue, "="); equalIndex != -1 {
+			publisher.Id = hrefValue[equalIndex+1:]
+		}
 	}
+	publisher.Imprints = imprints
+	publisher.SeriesLinks = seriesLinks
+	return publisher, nil
+}
 
-	return issue, nil
+// Parses a story arc's page and returns the corresponding struct.
+// The issues are listed as the series and number, such as `Astonishing X-Men (2004) #22`.
+func (p *CbParser) StoryArc(body io.Reader) (*StoryArc, error) {
+	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	if strings.Contains(doc.Text(), "mysql_connect()") {
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
+	}
+	content := doc.Find("td[width=\"850\"]").First()
+	storyArc := new(StoryArc)
+	storyArc.Name = strings.TrimSpace(content.Find(".page_headline").First().Text())
+	if storyArc.Name == "" {
+		return nil, &ParseError{Field: "headline", Cause: ErrParse}
+	}
+	issueLinks := make([]StoryArcIssueLink, 0)
+	content.Find("a").Each(func(i int, s *goquery.Selection) {
+		hrefValue, ex := s.Attr("href")
+		if !ex {
+			return
+		}
+		if storyArc.Id == "" && strings.HasPrefix(hrefValue, "storyarc_history.php") {
+			if equalIndex := strings.Index(hrefValue, "="); equalIndex != -1 {
+				storyArc.Id = hrefValue[equalIndex+1:]
+			}
+		}
+		text := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(hrefValue, "issue.php?ID=") || text == "" {
+			return
+		}
+		issueLink := StoryArcIssueLink{Url: fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue), Series: text}
+		if match := regStoryArcIssue.FindStringSubmatch(text); match != nil {
+			issueLink.Series = match[1]
+			issueLink.Number = match[2]
+		}
+		issueLinks = append(issueLinks, issueLink)
+	})
+	storyArc.IssueLinks = issueLinks
+	return storyArc, nil
 }
 
 func (p *CbParser) IssueLinks(body io.Reader) ([]string, error) {
 	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
 	if err != nil {
-		return nil, ErrParse
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
 	}
 	if strings.Contains(doc.Text(), "mysql_connect()") {
-		return nil, ErrConnection
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
 	}
 	issueLinks := make([]string, 0)
 	doc.Find("table").Each(func(i int, s *goquery.Selection) {
@@ -1,6 +1,7 @@
 package externalissuesource
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -8,6 +9,8 @@ import (
 	"unicode/utf8"
 	"sync"
 	"fmt"
+	"io/ioutil"
+	"strings"
 )
 
 func TestCbParser_parse(t *testing.T) {
@@ -39,6 +42,7 @@ func TestCbParser_Issue_TheEnd(t *testing.T) {
 	assert.Equal(t, "3152", issue.SeriesId)
 	assert.Equal(t, "X-Men: The End: Book 1: Dreamers & Demons (2004)", issue.Series)
 	assert.Equal(t, "Marvel", issue.Vendor)
+	assert.Equal(t, "4", issue.VendorId)
 	assert.Equal(t, 2004, int(issue.OnSaleDate.Year()))
 	assert.Equal(t, time.Month(8), issue.OnSaleDate.Month())
 	assert.Equal(t, 2004, int(issue.PublicationDate.Year()))
@@ -76,6 +80,7 @@ func TestCbParser_parseIssuePages(t *testing.T) {
 	assert.Equal(t, 10, int(issue.PublicationDate.Month()))
 	assert.Equal(t, 2007, int(issue.OnSaleDate.Year()))
 	assert.Equal(t, 8, int(issue.OnSaleDate.Month()))
+	assert.True(t, issue.OnSaleEstimated)
 	assert.Equal(t, "Astonishing X-Men (2004)", issue.Series)
 	assert.Equal(t, "22", issue.Number)
 	assert.Equal(t, "Marvel", issue.Vendor)
@@ -178,6 +183,8 @@ func TestCbParser_Issue_Mid_Date(t *testing.T) {
 	assert.Equal(t, i.OnSaleDate.Year(), 1989)
 	assert.Equal(t, i.PublicationDate.Month(), time.December)
 	assert.Equal(t, i.OnSaleDate.Month(), time.October)
+	assert.Equal(t, "Mid December 1989", i.CoverDate.Text)
+	assert.Equal(t, PrecisionMonth, i.CoverDate.Precision)
 	assert.Equal(t, Standard, i.Format)
 }
 
@@ -232,9 +239,29 @@ func TestCbParser_Issue_Dec_Jan(t *testing.T) {
 	assert.Equal(t, time.October, issue.OnSaleDate.Month())
 	assert.Equal(t, 1972, issue.PublicationDate.Year())
 	assert.Equal(t, time.January, issue.PublicationDate.Month())
+	assert.Equal(t, "Dec/Jan 1971", issue.CoverDate.Text)
+	assert.Equal(t, PrecisionDualMonth, issue.CoverDate.Precision)
+	assert.Equal(t, time.Date(1971, time.December, 1, 0, 0, 0, 0, time.UTC), issue.CoverDate.Earliest)
+	assert.Equal(t, time.Date(1972, time.January, 31, 0, 0, 0, 0, time.UTC), issue.CoverDate.Latest)
 	assert.Equal(t, Standard, issue.Format)
 }
 
+func TestCbParser_Issue_Jan_Feb(t *testing.T) {
+	// The Dec/Jan page with its cover date changed, since the months don't wrap into the next year.
+	bytes, err := ioutil.ReadFile("./testdata/cb_issue_dec_jan.html")
+	assert.Nil(t, err)
+	page := strings.Replace(string(bytes), "> Dec/Jan 1971", "> Jan/Feb 1972", 1)
+	parser := CbParser{}
+	issue, err := parser.Issue(strings.NewReader(page))
+	assert.Nil(t, err)
+	assert.Equal(t, "Jan/Feb 1972", issue.CoverDate.Text)
+	assert.Equal(t, time.Date(1972, time.January, 1, 0, 0, 0, 0, time.UTC), issue.CoverDate.Earliest)
+	assert.Equal(t, time.Date(1972, time.February, 29, 0, 0, 0, 0, time.UTC), issue.CoverDate.Latest)
+	assert.Equal(t, time.Date(1972, time.February, 1, 0, 0, 0, 0, time.UTC), issue.PublicationDate)
+	assert.Equal(t, time.Date(1971, time.November, 1, 0, 0, 0, 0, time.UTC), issue.OnSaleDate)
+	assert.True(t, issue.OnSaleEstimated)
+}
+
 func TestCbParser_Issue_Year_Only(t *testing.T) {
 	file, err := os.Open("./testdata/cb_issue_year_only.html")
 	defer file.Close()
@@ -250,6 +277,8 @@ func TestCbParser_Issue_Year_Only(t *testing.T) {
 	assert.Equal(t, time.January, issue.PublicationDate.Month())
 	assert.Equal(t, time.January, issue.OnSaleDate.Month())
 	assert.Equal(t, OGN, issue.Format)
+	assert.Equal(t, PrecisionYear, issue.CoverDate.Precision)
+	assert.Equal(t, time.Date(2014, time.December, 31, 0, 0, 0, 0, time.UTC), issue.CoverDate.Latest)
 }
 
 func TestCbParser_Issue_Jul_Sep(t *testing.T) {
@@ -317,6 +346,16 @@ func TestCbParser_CharacterSearch(t *testing.T) {
 	c, err := parser.CharacterSearch(file)
 	assert.Nil(t, err)
 	assert.Len(t, c.Results, 46)
+	assert.Equal(t, CharacterLink{
+		Url:            "http://comicbookdb.com/character.php?ID=9",
+		Name:           "Cyclops (Marvel)(03 - Scott Summers)",
+		Id:             "9",
+		Publisher:      "Marvel",
+		Disambiguation: "03 - Scott Summers",
+	}, c.Results[3])
+	assert.Equal(t, "DC", c.Results[0].Publisher)
+	assert.Equal(t, "Post Flashpoint", c.Results[0].Disambiguation)
+	assert.Equal(t, "", c.Results[44].Publisher)
 }
 
 func TestCbParser_Issue_No_Edit(t *testing.T) {
@@ -426,3 +465,228 @@ func TestCbParser_Issue_TimeExtraText(t *testing.T) {
 	_, err = parser.Issue(file)
 	assert.Nil(t, err)
 }
+
+func TestCbParser_Series(t *testing.T) {
+	// A synthetic title page, modeled on the layout of the real issue pages.
+	file, err := os.Open("./testdata/cb_title.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	series, err := parser.Series(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "439", series.Id)
+	assert.Equal(t, "Astonishing X-Men", series.Name)
+	assert.Equal(t, 2004, series.StartYear)
+	assert.Equal(t, "Marvel", series.Publisher)
+	assert.Equal(t, "3", series.Volume)
+	assert.Equal(t, Standard, series.Format)
+	assert.Len(t, series.IssueLinks, 8)
+	assert.Equal(t, SeriesIssueLink{Url: "http://comicbookdb.com/issue.php?ID=92205", Number: "1"}, series.IssueLinks[0])
+	assert.Equal(t, SeriesIssueLink{Url: "http://comicbookdb.com/issue.php?ID=92211", Number: "1", IsVariant: true, VariantName: "Dell'Otto Cover"}, series.IssueLinks[1])
+	assert.Equal(t, "(2nd Printing)", series.IssueLinks[2].VariantName)
+	assert.True(t, series.IssueLinks[6].IsVariant)
+	assert.Equal(t, "22", series.IssueLinks[6].Number)
+	assert.Equal(t, "Annual 1", series.IssueLinks[7].Number)
+	assert.False(t, series.IssueLinks[7].IsVariant)
+
+	file2, err := os.Open("./testdata/cb_error.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	_, err = parser.Series(file2)
+	assert.True(t, errors.Is(err, ErrConnection))
+}
+
+func TestCbParser_Series_RealLabels(t *testing.T) {
+	// The real issue pages have the same `<strong>Format:</strong> value<br>` labels as the title pages.
+	file, err := os.Open("./testdata/cb_issue.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	seriesPage, err := parser.Series(file)
+	assert.Nil(t, err)
+	assert.Equal(t, Standard, seriesPage.Format)
+}
+
+func TestCbParser_Publisher(t *testing.T) {
+	// Synthetic publisher pages, modeled on the layout of the real issue pages.
+	file, err := os.Open("./testdata/cb_publisher.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	publisher, err := parser.Publisher(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "4", publisher.Id)
+	assert.Equal(t, "Marvel", publisher.Name)
+	assert.Nil(t, publisher.Parent)
+	assert.Equal(t, 1939, publisher.YearBegan)
+	assert.Equal(t, 0, publisher.YearEnded)
+	assert.Equal(t, []PublisherLink{
+		{Url: "http://comicbookdb.com/publisher.php?ID=26", Name: "Epic Comics"},
+		{Url: "http://comicbookdb.com/publisher.php?ID=496", Name: "Icon"},
+		{Url: "http://comicbookdb.com/publisher.php?ID=376", Name: "MAX"},
+	}, publisher.Imprints)
+	assert.Len(t, publisher.SeriesLinks, 5)
+	assert.Equal(t, SeriesLink{Url: "http://comicbookdb.com/title.php?ID=439", Name: "Astonishing X-Men", StartYear: 2004}, publisher.SeriesLinks[0])
+	assert.Equal(t, "True Believers: Fantastic Four - The Wedding of Reed & Sue", publisher.SeriesLinks[4].Name)
+
+	file2, err := os.Open("./testdata/cb_publisher_imprint.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	imprint, err := parser.Publisher(file2)
+	assert.Nil(t, err)
+	assert.Equal(t, "26", imprint.Id)
+	assert.Equal(t, &PublisherLink{Url: "http://comicbookdb.com/publisher.php?ID=4", Name: "Marvel"}, imprint.Parent)
+	assert.Equal(t, 1982, imprint.YearBegan)
+	assert.Equal(t, 1996, imprint.YearEnded)
+	assert.Empty(t, imprint.Imprints)
+	assert.Len(t, imprint.SeriesLinks, 2)
+
+	file3, err := os.Open("./testdata/cb_error.html")
+	defer file3.Close()
+	assert.Nil(t, err)
+	_, err = parser.Publisher(file3)
+	assert.True(t, errors.Is(err, ErrConnection))
+}
+
+func TestCbParser_Issue_Credits(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Credits, 11)
+	assert.Equal(t, Credit{Role: RoleWriter, CreatorName: "Joss Whedon", CreatorId: "711", StoryIndex: WholeIssue}, issue.Credits[0])
+	assert.Equal(t, Credit{Role: RolePenciller, CreatorName: "John Cassaday", CreatorId: "107", StoryIndex: WholeIssue}, issue.Credits[1])
+	// The alias after the name is dropped.
+	assert.Equal(t, Credit{Role: RoleLetterer, CreatorName: "Chris Eliopoulos", CreatorId: "36", StoryIndex: WholeIssue}, issue.Credits[4])
+	assert.Equal(t, Credit{Role: RoleCoverArtist, CreatorName: "John Cassaday", CreatorId: "107", StoryIndex: WholeIssue}, issue.Credits[10])
+
+	// The issue has 3 stories with their own creators.
+	file2, err := os.Open("./testdata/cb_issue_dec_jan.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file2)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Credits, 13)
+	assert.Equal(t, Credit{Role: RoleEditor, CreatorName: "Julius Schwartz", CreatorId: "119", StoryIndex: WholeIssue}, issue.Credits[0])
+	assert.Equal(t, Credit{Role: RoleCoverArtist, CreatorName: "Neal Adams", CreatorId: "374", StoryIndex: WholeIssue}, issue.Credits[1])
+	assert.Equal(t, Credit{Role: RoleWriter, CreatorName: "Dennis 'Denny' O'Neil", CreatorId: "279", StoryIndex: 0}, issue.Credits[2])
+	assert.Equal(t, Credit{Role: RoleColorist, CreatorName: "Cornelia Adams", CreatorId: "1893", StoryIndex: 0}, issue.Credits[5])
+	assert.Equal(t, Credit{Role: RoleWriter, CreatorName: "Elliot S! Maggin", CreatorId: "562", StoryIndex: 1}, issue.Credits[7])
+	assert.Equal(t, Credit{Role: RoleInker, CreatorName: "Murphy Anderson", CreatorId: "486", StoryIndex: 2}, issue.Credits[12])
+}
+
+func TestCbParser_Issue_Characters(t *testing.T) {
+	// A synthetic copy of cb_issue.html with appearance notes added after some of the characters.
+	file, err := os.Open("./testdata/cb_issue_appearances.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Characters, 15)
+	assert.Equal(t, CharacterLink{Url: "http://comicbookdb.com/character.php?ID=4202", Name: "Armor (Marvel)"}, issue.Characters[0])
+	assert.Equal(t, CharacterLink{Url: "http://comicbookdb.com/character.php?ID=9", Name: "Cyclops (Marvel)(03 - Scott Summers)"}, issue.Characters[4])
+	assert.Equal(t, AppearanceCameo, issue.Characters[7].Appearance)
+	assert.Equal(t, CharacterLink{Url: "http://comicbookdb.com/character.php?ID=28646", Name: "Sydren (Marvel)", Appearance: AppearanceFirst}, issue.Characters[12])
+	assert.Equal(t, AppearanceFirst, issue.Characters[13].Appearance)
+	assert.Equal(t, AppearanceNone, issue.Characters[14].Appearance)
+
+	// Green Lantern appears in all 3 stories, but is only listed once.
+	file2, err := os.Open("./testdata/cb_issue_dec_jan.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file2)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Characters, 9)
+	assert.Equal(t, CharacterLink{Url: "http://comicbookdb.com/character.php?ID=35", Name: "Green Lantern (DC)(02 - Hal Jordan)"}, issue.Characters[0])
+	assert.Equal(t, "Abin Sur", issue.Characters[8].Name)
+
+	// The real character lists only have the links, and names such as `Deathstrike (Marvel)` aren't hints.
+	for _, fixture := range []string{"./testdata/cb_issue.html", "./testdata/cb_issue_hc.html"} {
+		file, err := os.Open(fixture)
+		assert.Nil(t, err)
+		issue, err := parser.Issue(file)
+		file.Close()
+		assert.Nil(t, err)
+		assert.NotEmpty(t, issue.Characters, fixture)
+		for _, character := range issue.Characters {
+			assert.Equal(t, AppearanceNone, character.Appearance, character.Name)
+		}
+	}
+}
+
+func TestCbParser_Issue_StoryArcs(t *testing.T) {
+	parser := CbParser{}
+	for fixture, expected := range map[string][]StoryArcLink{
+		"./testdata/cb_issue.html": {{Url: "http://comicbookdb.com/storyarc.php?ID=2002", Name: "Unstoppable"}},
+		"./testdata/cb_issue_format.html": {
+			{Url: "http://comicbookdb.com/storyarc.php?ID=3210", Name: "Dark Reign"},
+			{Url: "http://comicbookdb.com/storyarc.php?ID=3582", Name: "Utopia"},
+		},
+		// The arc is only listed on one of the stories.
+		"./testdata/cb_issue_no_reprint.html": {{Url: "http://comicbookdb.com/storyarc.php?ID=5519", Name: "GothTopia"}},
+		"./testdata/cb_issue_annual.html":     {},
+	} {
+		file, err := os.Open(fixture)
+		assert.Nil(t, err)
+		issue, err := parser.Issue(file)
+		file.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, issue.StoryArcs, fixture)
+	}
+}
+
+func TestCbParser_StoryArc(t *testing.T) {
+	// A synthetic story arc page, modeled on the layout of the real issue pages.
+	file, err := os.Open("./testdata/cb_storyarc.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	storyArc, err := parser.StoryArc(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "2002", storyArc.Id)
+	assert.Equal(t, "Unstoppable", storyArc.Name)
+	assert.Len(t, storyArc.IssueLinks, 7)
+	assert.Equal(t, StoryArcIssueLink{Url: "http://comicbookdb.com/issue.php?ID=92242", Series: "Astonishing X-Men (2004)", Number: "19"}, storyArc.IssueLinks[0])
+	assert.Equal(t, "http://comicbookdb.com/issue.php?ID=103298", storyArc.IssueLinks[3].Url)
+	assert.Equal(t, StoryArcIssueLink{Url: "http://comicbookdb.com/issue.php?ID=146592", Series: "Giant-Size Astonishing X-Men (2008)", Number: "1"}, storyArc.IssueLinks[6])
+
+	file2, err := os.Open("./testdata/cb_error.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	_, err = parser.StoryArc(file2)
+	assert.True(t, errors.Is(err, ErrConnection))
+}
+
+func TestCbParser_Issue_Versions(t *testing.T) {
+	parser := CbParser{}
+	file, err := os.Open("./testdata/cb_issue_reprint.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "46083", issue.OriginalIssueId)
+	assert.Equal(t, &IssueLink{Url: "http://comicbookdb.com/issue.php?ID=46083", Id: "46083", Title: "Classic X-Men (1986) #7 - Deathstar, Rising!"}, issue.VersionOf)
+	assert.Len(t, issue.Versions, 0)
+
+	file2, err := os.Open("./testdata/cb_issue_no_reprint.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file2)
+	assert.Nil(t, err)
+	assert.Equal(t, "", issue.OriginalIssueId)
+	assert.Nil(t, issue.VersionOf)
+	assert.Len(t, issue.Versions, 7)
+	assert.Equal(t, "293009", issue.Versions[0].Id)
+	assert.Equal(t, "Detective Comics (2011) #27 Burnham Variant", issue.Versions[0].Title)
+
+	file3, err := os.Open("./testdata/cb_issue.html")
+	defer file3.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file3)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Versions, 1)
+	assert.Equal(t, "103305", issue.Versions[0].Id)
+}
@@ -0,0 +1,216 @@
+package externalissuesource
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The audience a publisher rates an issue for.
+type Rating int
+
+// Ratings for issues.
+const (
+	RatingUnknown  Rating = iota // The source doesn't have a rating, or it isn't one of the known ratings.
+	RatingAllAges                // Suitable for all ages, such as "All Ages" or "E".
+	RatingTeen                   // Suitable for teens, such as "T" or "Teen".
+	RatingTeenPlus               // Suitable for older teens, such as "T+" or "Parental Advisory".
+	RatingMature                 // For adults, such as "Mature", "Explicit Content" or "MAX".
+)
+
+var ratingNames = [...]string{"", "all ages", "teen", "teen plus", "mature"}
+
+// The ratings for each label the publishers use, in lowercase without "Rated".
+var ratingLabels = map[string]Rating{
+	"a":                 RatingAllAges,
+	"e":                 RatingAllAges,
+	"all ages":          RatingAllAges,
+	"everyone":          RatingAllAges,
+	"t":                 RatingTeen,
+	"teen":              RatingTeen,
+	"teens and up":      RatingTeen,
+	"t+":                RatingTeenPlus,
+	"teen+":             RatingTeenPlus,
+	"teen plus":         RatingTeenPlus,
+	"teens plus":        RatingTeenPlus,
+	"parental advisory": RatingTeenPlus,
+	"pa":                RatingTeenPlus,
+	"m":                 RatingMature,
+	"mature":            RatingMature,
+	"mature readers":    RatingMature,
+	"explicit":          RatingMature,
+	"explicit content":  RatingMature,
+	"max":               RatingMature,
+	"18+":               RatingMature,
+	"adults only":       RatingMature,
+}
+
+// The ISO 4217 codes for the currencies cb puts before the price, such as `US $ 2.99` or `Euro € 4.55`.
+var cbCurrencies = map[string]string{
+	"US":   "USD",
+	"CAN":  "CAD",
+	"CDN":  "CAD",
+	"UK":   "GBP",
+	"AUS":  "AUD",
+	"NZ":   "NZD",
+	"EURO": "EUR",
+	"YEN":  "JPY",
+}
+
+var (
+	// A price with the currency before the amount on cb, such as `US $ 2.99`, or after it on GCD, such as `2.99 USD`.
+	regPriceCurrencyFirst = regexp.MustCompile(`^(.*?)\s*(\d+(?:[.,]\d+)?)$`)
+	regPriceCurrencyLast  = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)\s*([A-Za-z]{3})$`)
+	regPageCount          = regexp.MustCompile(`(\d+) pages?\b`)
+	// An ISBN-13 or ISBN-10 in an issue's notes, such as `ISBN: 978-1-4012-5124-6` or `ISBN 0-425-16448-9.`,
+	// and a UPC, such as `UPC: 7 59606 05514 1 02211`.
+	regNotesIsbn = regexp.MustCompile(`(?i)\bISBN(?:-1[03])?:?\s*(\d(?:[ -]?\d){12}|\d(?:[ -]?\d){8}[ -]?[\dX])\b`)
+	regNotesUpc  = regexp.MustCompile(`(?i)\bUPC:?\s*(\d(?:[ -]?\d){11,16})\b`)
+)
+
+// Gets the name of the rating, such as `teen plus`, or an empty string for `RatingUnknown`.
+func (r Rating) String() string {
+	if r < 0 || int(r) >= len(ratingNames) {
+		return ratingNames[RatingUnknown]
+	}
+	return ratingNames[r]
+}
+
+// Parses a rating, such as "Mature", "Rated T+" or "Parental Advisory". Unknown ratings are `RatingUnknown`.
+func ParseRating(text string) Rating {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.TrimSpace(strings.TrimPrefix(text, "rated"))
+	return ratingLabels[text]
+}
+
+// The price printed on an issue's cover.
+type Price struct {
+	Amount   int64  // The price in hundredths of the currency, such as `299` for $2.99. 0 for free issues.
+	Currency string // The ISO 4217 code of the currency, such as `USD`, or the source's text if it's unknown. Empty for free issues.
+}
+
+// Parses a cover price, such as `US $ 2.99` on cb or `2.99 USD; 3.75 CAD` on GCD, which only keeps the first price.
+// `Free` is a price of 0. Returns nil if there isn't a price.
+func parsePrice(text string) *Price {
+	if semiColonIndex := strings.Index(text, ";"); semiColonIndex != -1 {
+		text = text[:semiColonIndex]
+	}
+	text = strings.TrimSpace(text)
+	if strings.EqualFold(text, "free") {
+		return &Price{}
+	}
+	var amountText, currency string
+	if match := regPriceCurrencyLast.FindStringSubmatch(text); match != nil {
+		amountText, currency = match[1], strings.ToUpper(match[2])
+	} else if match := regPriceCurrencyFirst.FindStringSubmatch(text); match != nil {
+		amountText, currency = match[2], strings.TrimSpace(match[1])
+		if fields := strings.Fields(currency); len(fields) > 0 {
+			if code, ok := cbCurrencies[strings.ToUpper(fields[0])]; ok {
+				currency = code
+			}
+		}
+	} else {
+		return nil
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(amountText, ",", ".", 1), 64)
+	if err != nil {
+		return nil
+	}
+	return &Price{Amount: int64(math.Round(amount * 100)), Currency: currency}
+}
+
+// Keeps only the digits of a barcode, such as `7 59606 05514 1 02211`.
+func normalizeBarcode(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// Keeps only the digits and the `X` check digit of an ISBN, such as `0-7851-0822-3`.
+// GCD separates the ISBN-10 and ISBN-13 with a semicolon, so only the first one is kept.
+func normalizeIsbn(text string) string {
+	if semiColonIndex := strings.Index(text, ";"); semiColonIndex != -1 {
+		text = text[:semiColonIndex]
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == 'x' || r == 'X':
+			return 'X'
+		}
+		return -1
+	}, text)
+}
+
+// Sets the barcode on the issue unless it already has one. Barcodes that are ISBN-13s (starting with 978 or 979)
+// are set as the ISBN and the rest as the UPC.
+func setBarcode(issue *Issue, barcode string) {
+	barcode = normalizeBarcode(barcode)
+	if len(barcode) == 13 && (strings.HasPrefix(barcode, "978") || strings.HasPrefix(barcode, "979")) {
+		if issue.ISBN == "" {
+			issue.ISBN = barcode
+		}
+	} else if issue.UPC == "" {
+		issue.UPC = barcode
+	}
+}
+
+// Gets the page count from the format, such as `Color; Standard Comic Issue; 32 pages`, or 0 if it doesn't have one.
+func parsePageCount(text string) int {
+	if match := regPageCount.FindStringSubmatch(text); match != nil {
+		pageCount, _ := strconv.Atoi(match[1])
+		return pageCount
+	}
+	return 0
+}
+
+// Gets the values after each of the labels in the cb issue's details, such as `Cover Price:` => `US $ 2.99`,
+// from `<strong>Cover Price:</strong> US $ 2.99<br>`.
+func cbLabeledValues(s *goquery.Selection) map[string]string {
+	values := make(map[string]string)
+	label := ""
+	s.Contents().Each(func(i int, c *goquery.Selection) {
+		if goquery.NodeName(c) == "strong" {
+			label = strings.TrimSpace(c.Text())
+			return
+		}
+		if label == "" {
+			return
+		}
+		if goquery.NodeName(c) == "br" {
+			values[label] += " "
+			return
+		}
+		values[label] += c.Text()
+	})
+	for label, value := range values {
+		values[label] = strings.Join(strings.Fields(value), " ")
+	}
+	return values
+}
+
+// Sets the product details from cb's labels for the issue: the cover price and page count, and the ISBN and UPC
+// when the notes mention them, such as `ISBN: 978-1-4012-5124-6`. cb's "Rating" is the users' score out of 10,
+// so cb issues don't have a rating.
+func cbProductDetails(issue *Issue, values map[string]string) {
+	if price, ok := values["Cover Price:"]; ok && issue.CoverPrice == nil {
+		issue.CoverPrice = parsePrice(price)
+	}
+	if format, ok := values["Format:"]; ok && issue.PageCount == 0 {
+		issue.PageCount = parsePageCount(format)
+	}
+	if notes, ok := values["Notes:"]; ok {
+		if match := regNotesIsbn.FindStringSubmatch(notes); match != nil && issue.ISBN == "" {
+			issue.ISBN = normalizeIsbn(match[1])
+		}
+		if match := regNotesUpc.FindStringSubmatch(notes); match != nil && issue.UPC == "" {
+			issue.UPC = normalizeBarcode(match[1])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestParsePrice(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected *Price
+	}{
+		{text: "US $ 2.99", expected: &Price{Amount: 299, Currency: "USD"}},
+		{text: "US $ 0.25", expected: &Price{Amount: 25, Currency: "USD"}},
+		{text: "Euro € 4.55", expected: &Price{Amount: 455, Currency: "EUR"}},
+		{text: "CAN $ 3.75", expected: &Price{Amount: 375, Currency: "CAD"}},
+		{text: "Free", expected: &Price{}},
+		{text: "2.99 USD; 3.75 CAD", expected: &Price{Amount: 299, Currency: "USD"}},
+		{text: "24.95 USD", expected: &Price{Amount: 2495, Currency: "USD"}},
+		{text: "Peso 35", expected: &Price{Amount: 3500, Currency: "Peso"}},
+		{text: "4,55 EUR", expected: &Price{Amount: 455, Currency: "EUR"}},
+		{text: "", expected: nil},
+		{text: "None.", expected: nil},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, parsePrice(testCase.text), testCase.text)
+	}
+}
+
+func TestParseRating(t *testing.T) {
+	for text, expected := range map[string]Rating{
+		"All Ages":                              RatingAllAges,
+		"Rated T":                               RatingTeen,
+		"T+":                                    RatingTeenPlus,
+		"Parental Advisory":                     RatingTeenPlus,
+		"Mature":                                RatingMature,
+		"Explicit Content":                      RatingMature,
+		"MAX":                                   RatingMature,
+		"Approved by the Comics Code Authority": RatingUnknown,
+		"":                                      RatingUnknown,
+	} {
+		assert.Equal(t, expected, ParseRating(text), text)
+	}
+}
+
+func TestSetBarcode(t *testing.T) {
+	issue := &Issue{}
+	setBarcode(issue, "7 59606 05514 1 02211")
+	assert.Equal(t, "75960605514102211", issue.UPC)
+	assert.Equal(t, "", issue.ISBN)
+
+	// ISBN-13 barcodes on trades are the ISBN.
+	issue = &Issue{}
+	setBarcode(issue, "978-0-7851-8827-8")
+	assert.Equal(t, "", issue.UPC)
+	assert.Equal(t, "9780785188278", issue.ISBN)
+}
+
+func TestNormalizeIsbn(t *testing.T) {
+	assert.Equal(t, "0785108223", normalizeIsbn("0-7851-0822-3"))
+	assert.Equal(t, "078510822X", normalizeIsbn("0-7851-0822-x"))
+	assert.Equal(t, "0785108223", normalizeIsbn("0-7851-0822-3; 978-0-7851-0822-1"))
+}
+
+func TestCbProductDetails(t *testing.T) {
+	issue := &Issue{}
+	cbProductDetails(issue, map[string]string{
+		"Cover Price:": "US $ 34.99",
+		"Format:":      "Color; Trade Paperback; 296 pages",
+		"Notes:":       "Collects issues #1-6. ISBN-13: 978-0-7851-8827-8 UPC: 7 59606 05514 1 02211",
+		"Rating:":      "8.5",
+	})
+	assert.Equal(t, &Price{Amount: 3499, Currency: "USD"}, issue.CoverPrice)
+	assert.Equal(t, 296, issue.PageCount)
+	assert.Equal(t, "9780785188278", issue.ISBN)
+	assert.Equal(t, "75960605514102211", issue.UPC)
+	assert.Equal(t, RatingUnknown, issue.Rating)
+}
+
+func TestCbParser_Issue_ProductDetails(t *testing.T) {
+	parser := CbParser{}
+	// The ISBN is in the notes.
+	file, err := os.Open("./testdata/cb_issue_year_only.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, &Price{Amount: 1999, Currency: "USD"}, issue.CoverPrice)
+	assert.Equal(t, "9781401251246", issue.ISBN)
+	assert.Equal(t, "", issue.UPC)
+	assert.Equal(t, RatingUnknown, issue.Rating)
+
+	file1, err := os.Open("./testdata/cb_issue_nn.html")
+	defer file1.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file1)
+	assert.Nil(t, err)
+	assert.Equal(t, &Price{Amount: 699, Currency: "USD"}, issue.CoverPrice)
+	assert.Equal(t, 345, issue.PageCount)
+	assert.Equal(t, "0425164489", issue.ISBN)
+
+	file2, err := os.Open("./testdata/cb_issue_deutschland.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file2)
+	assert.Nil(t, err)
+	assert.Equal(t, &Price{Amount: 455, Currency: "EUR"}, issue.CoverPrice)
+	assert.Equal(t, 48, issue.PageCount)
+	assert.Equal(t, RatingUnknown, issue.Rating)
+
+	file3, err := os.Open("./testdata/cb_issue_digital.html")
+	defer file3.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file3)
+	assert.Nil(t, err)
+	assert.Equal(t, &Price{}, issue.CoverPrice)
+	assert.Equal(t, 9, issue.PageCount)
+
+	// Trades without a page count in the format.
+	file4, err := os.Open("./testdata/cb_issue_tpb.html")
+	defer file4.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file4)
+	assert.Nil(t, err)
+	assert.Equal(t, &Price{Amount: 3499, Currency: "USD"}, issue.CoverPrice)
+	assert.Equal(t, 0, issue.PageCount)
+	assert.Equal(t, "", issue.UPC)
+}
+
+func TestRegNotesIsbn(t *testing.T) {
+	for text, expected := range map[string]string{
+		"ISBN: 978-1-4012-5124-6": "978-1-4012-5124-6",
+		"A Berkley Boulevard Book. ISBN 0-425-16448-9. Cover Art by X.": "0-425-16448-9",
+		"isbn 078510822X":                   "078510822X",
+		"Reprints issues #1-12, 1987-1988.": "",
+	} {
+		match := regNotesIsbn.FindStringSubmatch(text)
+		if expected == "" {
+			assert.Nil(t, match, text)
+			continue
+		}
+		assert.Equal(t, expected, match[1], text)
+	}
+}
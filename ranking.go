@@ -0,0 +1,139 @@
+package externalissuesource
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	// The qualifiers after a character's name, such as `(Marvel)(03 - Scott Summers)` on cb
+	// or `[Greek mythology]` on GCD.
+	regCharacterParens  = regexp.MustCompile(`\s*\(([^()]*)\)$`)
+	regCharacterBracket = regexp.MustCompile(`\s*\[([^\[\]]*)\]$`)
+)
+
+// Splits a character's name from a search result into the name on its own, the publisher and the disambiguation.
+// cb puts the publisher in the first parentheses and the universe or version in the ones after it,
+// such as `Batman (DC)(Post Flashpoint)(01 - Bruce Wayne)`, and GCD puts the disambiguation in brackets.
+func splitCharacterName(name string) (string, string, string) {
+	name = strings.TrimSpace(name)
+	disambiguations := make([]string, 0)
+	if match := regCharacterBracket.FindStringSubmatchIndex(name); match != nil {
+		disambiguations = append(disambiguations, strings.TrimSpace(name[match[2]:match[3]]))
+		name = name[:match[0]]
+	}
+	qualifiers := make([]string, 0)
+	for {
+		match := regCharacterParens.FindStringSubmatchIndex(name)
+		if match == nil || match[0] == 0 {
+			break
+		}
+		qualifiers = append([]string{strings.TrimSpace(name[match[2]:match[3]])}, qualifiers...)
+		name = name[:match[0]]
+	}
+	publisher := ""
+	if len(qualifiers) > 0 {
+		publisher = qualifiers[0]
+		disambiguations = append(qualifiers[1:], disambiguations...)
+	}
+	return strings.TrimSpace(name), publisher, strings.Join(disambiguations, ", ")
+}
+
+// Makes the character link for a search result. The publisher shown next to the result is kept
+// over the one in the name.
+func searchCharacterLink(entry searchEntry) CharacterLink {
+	_, publisher, disambiguation := splitCharacterName(entry.Name)
+	if entry.Publisher != "" {
+		publisher = entry.Publisher
+	}
+	return CharacterLink{
+		Url:            entry.Url,
+		Name:           entry.Name,
+		Id:             entry.Id,
+		Publisher:      publisher,
+		Disambiguation: disambiguation,
+	}
+}
+
+// Lowercases the name and keeps only its words, so `Scott 'Cyclops' Summers` is `scott cyclops summers`.
+func normalizeCharacterName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// How well a character's name matches a query, from the best match to none.
+const (
+	characterMatchExact   = iota // The name without its publisher and disambiguation is the query.
+	characterMatchWord           // The query is one or more whole words in the name, such as `Doctor Cyclops`.
+	characterMatchPartial        // The query is part of a word in the name.
+	characterMatchNone
+)
+
+// Gets how well the character's name matches the normalized query.
+func characterMatch(query string, name string) int {
+	name = normalizeCharacterName(name)
+	switch {
+	case name == query:
+		return characterMatchExact
+	case strings.Contains(" "+name+" ", " "+query+" "):
+		return characterMatchWord
+	case strings.Contains(name, query):
+		return characterMatchPartial
+	}
+	return characterMatchNone
+}
+
+// Ranks the characters from a search against the query so the best match is first, such as for linking
+// a name to a character automatically. Characters whose name without the publisher and disambiguation is
+// the query come first, then the ones with the query as a whole word in their name, then the rest.
+// Within each, the characters from the preferred publishers come first in the order they're given,
+// then the ones without a disambiguation. Otherwise the source's order is kept. The characters aren't modified.
+func RankCharacters(query string, characters []CharacterLink, publishers ...string) []CharacterLink {
+	query = normalizeCharacterName(query)
+	type rankedCharacter struct {
+		link              CharacterLink
+		match             int
+		publisher         int
+		hasDisambiguation bool
+	}
+	ranked := make([]rankedCharacter, 0, len(characters))
+	for _, link := range characters {
+		name, publisher, disambiguation := splitCharacterName(link.Name)
+		if link.Publisher != "" {
+			publisher = link.Publisher
+		}
+		if link.Disambiguation != "" {
+			disambiguation = link.Disambiguation
+		}
+		publisherRank := len(publishers)
+		for idx, preferred := range publishers {
+			if strings.EqualFold(strings.TrimSpace(preferred), publisher) {
+				publisherRank = idx
+				break
+			}
+		}
+		ranked = append(ranked, rankedCharacter{
+			link:              link,
+			match:             characterMatch(query, name),
+			publisher:         publisherRank,
+			hasDisambiguation: disambiguation != "",
+		})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].match != ranked[j].match {
+			return ranked[i].match < ranked[j].match
+		}
+		if ranked[i].publisher != ranked[j].publisher {
+			return ranked[i].publisher < ranked[j].publisher
+		}
+		return !ranked[i].hasDisambiguation && ranked[j].hasDisambiguation
+	})
+	rankedCharacters := make([]CharacterLink, len(ranked))
+	for idx, character := range ranked {
+		rankedCharacters[idx] = character.link
+	}
+	return rankedCharacters
+}
@@ -0,0 +1,47 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSplitCharacterName(t *testing.T) {
+	for fullName, expected := range map[string][3]string{
+		"Cyclops (Marvel)(03 - Scott Summers)":           {"Cyclops", "Marvel", "03 - Scott Summers"},
+		"Batman (DC)(Post Flashpoint)(01 - Bruce Wayne)": {"Batman", "DC", "Post Flashpoint, 01 - Bruce Wayne"},
+		"Doctor Cyclops (DC)":                            {"Doctor Cyclops", "DC", ""},
+		"Cyclops [Greek mythology]":                      {"Cyclops", "", "Greek mythology"},
+		"Corporal Scott 'Cyclops' Summers":               {"Corporal Scott 'Cyclops' Summers", "", ""},
+		"(Unnamed)":                                      {"(Unnamed)", "", ""},
+	} {
+		name, publisher, disambiguation := splitCharacterName(fullName)
+		assert.Equal(t, expected, [3]string{name, publisher, disambiguation}, fullName)
+	}
+}
+
+func TestRankCharacters(t *testing.T) {
+	file, err := os.Open("./testdata/cyclops/search.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	result, err := parser.CharacterSearch(file)
+	assert.Nil(t, err)
+
+	ranked := RankCharacters("Cyclops", result.Results, "Marvel")
+	assert.Len(t, ranked, 46)
+	assert.Equal(t, "Cyclops (Marvel)(01 - Olympian monster)", ranked[0].Name)
+	// The exact matches from other publishers are next, the ones without a disambiguation first.
+	assert.Equal(t, "Cyclops (Monster in My Pocket)", ranked[35].Name)
+	assert.Equal(t, "Cyclops (DC)(Post Flashpoint)", ranked[38].Name)
+	assert.Equal(t, "Polyphemus the Cyclops (Marvel)", ranked[39].Name)
+	assert.Equal(t, "Ulysses (Marvel)(04 - Cyclops villain)", ranked[45].Name)
+
+	ranked = RankCharacters(" cyclops ", result.Results, "dc", "Marvel")
+	assert.Equal(t, "Cyclops (DC)(Post Flashpoint)", ranked[0].Name)
+	assert.Equal(t, "Cyclops (Marvel)(01 - Olympian monster)", ranked[1].Name)
+
+	// The order of the results isn't changed.
+	assert.Equal(t, "Cyclops (DC)(Post Flashpoint)", result.Results[0].Name)
+	assert.Len(t, RankCharacters("cyclops", []CharacterLink{}), 0)
+}
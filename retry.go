@@ -0,0 +1,123 @@
+package externalissuesource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// The default retry policy for `CbExternalSource`: up to 3 attempts, waiting 1s and then 2s between them.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 1 * time.Second,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+}
+
+// The status codes that are retried when a policy doesn't list its own.
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Decides whether failed requests are retried and how long to wait between attempts.
+// The wait grows exponentially: InitialBackoff, InitialBackoff*Multiplier, ... up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts          int                  // The total number of attempts, including the first one. 1 or less never retries.
+	InitialBackoff       time.Duration        // The wait before the second attempt.
+	MaxBackoff           time.Duration        // The longest wait between attempts. Not capped if not provided.
+	Multiplier           float64              // How much the wait grows after each attempt. Default is 2 if not provided.
+	RetryableStatusCodes []int                // The status codes to retry. Default is 429 and 5xx gateway/server errors if not provided.
+	Retryable            func(err error) bool // Overrides which errors are retried if provided.
+}
+
+// Whether the error is worth another attempt. By default that's `ErrConnection`, network errors,
+// and bad status codes in `RetryableStatusCodes`.
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	if errors.Is(err, ErrConnection) {
+		return true
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		statusCodes := p.RetryableStatusCodes
+		if len(statusCodes) == 0 {
+			statusCodes = defaultRetryableStatusCodes
+		}
+		for _, statusCode := range statusCodes {
+			if statusErr.StatusCode == statusCode {
+				return true
+			}
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Gets how long to wait after the attempt failed.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= multiplier
+		if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(backoff)
+}
+
+// Calls fetch until it succeeds, the error isn't retryable, the attempts run out or the context is done.
+// Each call to fetch must send a new request and read its body again.
+func (p *RetryPolicy) do(ctx context.Context, fetch func() error) error {
+	attempts := 0
+	for {
+		attempts++
+		err := fetch()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempts >= p.MaxAttempts || !p.retryable(err) {
+			if attempts > 1 {
+				return &RetryError{Attempts: attempts, Err: err}
+			}
+			return err
+		}
+		timer := time.NewTimer(p.backoff(attempts))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Returned when a request was retried and still failed.
+type RetryError struct {
+	Attempts int   // The number of attempts that were made.
+	Err      error // The error from the last attempt.
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %s", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
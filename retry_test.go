@@ -0,0 +1,106 @@
+package externalissuesource
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	policy := RetryPolicy{}
+	assert.True(t, policy.retryable(ErrConnection))
+	assert.True(t, policy.retryable(&HTTPStatusError{URL: "http://comicbookdb.com", StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, policy.retryable(&HTTPStatusError{URL: "http://comicbookdb.com", StatusCode: http.StatusNotFound}))
+	assert.False(t, policy.retryable(ErrParse))
+
+	policy.RetryableStatusCodes = []int{http.StatusNotFound}
+	assert.True(t, policy.retryable(&HTTPStatusError{URL: "http://comicbookdb.com", StatusCode: http.StatusNotFound}))
+}
+
+// Serves the connection error page for the first `failures` requests and then the issue page.
+func flakyIssueServer(failures int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		path := "./testdata/cb_issue.html"
+		if *requests <= failures {
+			path = "./testdata/cb_error.html"
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+}
+
+func TestCbExternalSource_Retry_ErrConnection(t *testing.T) {
+	requests := 0
+	ts := flakyIssueServer(2, &requests)
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+		retry:      &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+	issue, err := externalSource.Issue(ts.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "22", issue.Number)
+	assert.Equal(t, 3, requests)
+}
+
+func TestCbExternalSource_Retry_GivesUp(t *testing.T) {
+	requests := 0
+	ts := flakyIssueServer(5, &requests)
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+		retry:      &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+	_, err := externalSource.CharacterPage(ts.URL)
+	retryErr, ok := err.(*RetryError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.True(t, errors.Is(err, ErrConnection))
+	assert.Equal(t, 3, requests)
+}
+
+func TestCbExternalSource_Retry_NotRetryable(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+		retry:      &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+	_, err := externalSource.SearchCharacter("cyclops")
+	var statusErr *HTTPStatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, 1, requests)
+}
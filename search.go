@@ -0,0 +1,284 @@
+package externalissuesource
+
+import (
+	"context"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/aimeelaplant/externalissuesource/internal/stringutil"
+	"golang.org/x/text/encoding/charmap"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// What kind of entity to search for.
+type SearchType int
+
+// Kinds of entities to search for.
+const (
+	SearchTitle     SearchType = iota // Series, which cb calls titles.
+	SearchCreator                     // Writers, artists and the other creators.
+	SearchPublisher                   // Publishers and imprints.
+	SearchStoryArc                    // Story arcs. GCD doesn't have them.
+	SearchCharacter                   // Characters, like `SearchCharacter`.
+)
+
+var searchTypeNames = [...]string{"title", "creator", "publisher", "story arc", "character"}
+
+// The value of cb's `form_searchtype` for each search type and the page its results link to.
+var (
+	cbSearchTypes = [...]string{"Title", "Creator", "Publisher", "Storyarc", "Character"}
+	cbSearchPages = [...]string{"title.php?ID=", "creator.php?ID=", "publisher.php?ID=", "storyarc.php?ID=", "character.php?ID="}
+)
+
+// The value of GCD's `search_object` for each search type and the page its results link to.
+// An empty value means GCD doesn't have that kind of entity.
+var (
+	gcdSearchObjects = [...]string{"series", "creator", "publisher", "", "character"}
+	gcdSearchPages   = [...]string{"/series/", "/creator/", "/publisher/", "", "/character/"}
+)
+
+// Gets the name of the search type, such as `story arc`.
+func (t SearchType) String() string {
+	if !t.valid() {
+		return ""
+	}
+	return searchTypeNames[t]
+}
+
+func (t SearchType) valid() bool {
+	return t >= 0 && int(t) < len(searchTypeNames)
+}
+
+// A link to a creator with their URL and name.
+type CreatorLink struct {
+	Url  string
+	Name string
+}
+
+// The results of a search. Only the results for its type are set, in the order the source lists them.
+type SearchResult struct {
+	Query      string // The query as the source shows it.
+	Type       SearchType
+	Titles     []SeriesLink // The series, with their start year and publisher when they're shown.
+	Creators   []CreatorLink
+	Publishers []PublisherLink
+	StoryArcs  []StoryArcLink // The story arcs, with their publisher when it's shown.
+	Characters []CharacterLink
+}
+
+// One result as it's shown on a search page, before it's added to the result's links for its type.
+type searchEntry struct {
+	Url       string
+	Id        string
+	Name      string
+	Publisher string
+	Year      int
+}
+
+func newSearchResult(searchType SearchType) *SearchResult {
+	return &SearchResult{
+		Type:       searchType,
+		Titles:     make([]SeriesLink, 0),
+		Creators:   make([]CreatorLink, 0),
+		Publishers: make([]PublisherLink, 0),
+		StoryArcs:  make([]StoryArcLink, 0),
+		Characters: make([]CharacterLink, 0),
+	}
+}
+
+// Adds the entry to the links for the result's type. A series' year is taken from its name,
+// such as `Astonishing X-Men (2004)`, when the source doesn't show it on its own.
+func (r *SearchResult) add(entry searchEntry) {
+	switch r.Type {
+	case SearchTitle:
+		seriesLink := SeriesLink{Url: entry.Url, Name: entry.Name, StartYear: entry.Year, Publisher: entry.Publisher}
+		if match := regSeriesYear.FindStringSubmatch(entry.Name); match != nil {
+			seriesLink.Name = match[1]
+			seriesLink.StartYear, _ = strconv.Atoi(match[2])
+		}
+		r.Titles = append(r.Titles, seriesLink)
+	case SearchCreator:
+		r.Creators = append(r.Creators, CreatorLink{Url: entry.Url, Name: entry.Name})
+	case SearchPublisher:
+		r.Publishers = append(r.Publishers, PublisherLink{Url: entry.Url, Name: entry.Name})
+	case SearchStoryArc:
+		r.StoryArcs = append(r.StoryArcs, StoryArcLink{Url: entry.Url, Name: entry.Name, Publisher: entry.Publisher})
+	case SearchCharacter:
+		r.Characters = append(r.Characters, searchCharacterLink(entry))
+	}
+}
+
+// Parses the results of the type from cb's search page. Each result is a link followed by an optional publisher,
+// such as `<a href="title.php?ID=1">Astonishing X-Men (2004)</a> (Marvel)<br>`.
+func (p *CbParser) Search(body io.Reader, searchType SearchType) (*SearchResult, error) {
+	if !searchType.valid() {
+		return nil, ErrUnsupported
+	}
+	doc, err := goquery.NewDocumentFromReader(charmap.ISO8859_1.NewDecoder().Reader(body))
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	if strings.Contains(doc.Text(), "mysql_connect()") {
+		return nil, &ParseError{Field: "document", Cause: ErrConnection}
+	}
+	result := newSearchResult(searchType)
+	page := cbSearchPages[searchType]
+	var entry *searchEntry
+	label, trailing := "", ""
+	flush := func() {
+		if entry != nil {
+			if entry.Publisher == "" {
+				entry.Publisher = cbSearchPublisher(trailing)
+			}
+			result.add(*entry)
+		}
+		entry, trailing = nil, ""
+	}
+	doc.Find("td[width=\"850\"]").First().Contents().Each(func(i int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "strong":
+			label = strings.TrimSpace(s.Text())
+		case "a":
+			hrefValue, ex := s.Attr("href")
+			switch {
+			case !ex:
+			case strings.HasPrefix(hrefValue, page):
+				flush()
+				entry = &searchEntry{
+					Url:  fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue),
+					Id:   hrefValue[strings.Index(hrefValue, "=")+1:],
+					Name: strings.TrimSpace(s.Text()),
+				}
+			case entry != nil && strings.HasPrefix(hrefValue, "publisher.php?ID="):
+				entry.Publisher = strings.TrimSpace(s.Text())
+			}
+		case "br":
+			flush()
+		case "#text":
+			if label == "Your search:" {
+				result.Query = strings.TrimSpace(s.Text())
+				label = ""
+			} else if entry != nil {
+				trailing += s.Text()
+			}
+		}
+	})
+	flush()
+	return result, nil
+}
+
+// Gets the publisher from the text after a cb search result, such as ` (Marvel)` or ` - Marvel`.
+func cbSearchPublisher(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSpace(strings.TrimPrefix(text, "-"))
+	if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	return text
+}
+
+// Parses the results of the type from GCD's search page, which lists each result in a row
+// with its publisher and year, such as the year a series began.
+func (p *GcdParser) Search(body io.Reader, searchType SearchType) (*SearchResult, error) {
+	if !searchType.valid() || gcdSearchPages[searchType] == "" {
+		return nil, ErrUnsupported
+	}
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, &ParseError{Field: "document", Cause: ErrParse}
+	}
+	result := newSearchResult(searchType)
+	heading := strings.TrimSpace(doc.Find("h2").First().Text())
+	result.Query = strings.Trim(strings.TrimPrefix(heading, "Search results for "), "\"")
+	doc.Find("#search_results tr").Each(func(i int, row *goquery.Selection) {
+		link := row.Find(fmt.Sprintf("a[href^=%q]", gcdSearchPages[searchType])).First()
+		hrefValue, ex := link.Attr("href")
+		if !ex {
+			return
+		}
+		entry := searchEntry{
+			Url:  fmt.Sprintf("%s%s", p.BaseUrl(), hrefValue),
+			Id:   gcdId(hrefValue, strings.Trim(gcdSearchPages[searchType], "/")),
+			Name: strings.TrimSpace(link.Text()),
+		}
+		if searchType != SearchPublisher {
+			entry.Publisher = strings.TrimSpace(row.Find("a[href^=\"/publisher/\"]").First().Text())
+		}
+		row.Find("td").Each(func(j int, cell *goquery.Selection) {
+			if match := regY.FindStringSubmatch(strings.TrimSpace(cell.Text())); match != nil {
+				entry.Year, _ = strconv.Atoi(match[1])
+			}
+		})
+		result.add(entry)
+	})
+	return result, nil
+}
+
+// Searches the entities of the type on the provided query.
+func (s *CbExternalSource) Search(query string, searchType SearchType) (SearchResult, error) {
+	return s.SearchContext(context.Background(), query, searchType)
+}
+
+// Searches the entities of the type on the provided query. The request is canceled when the context is done.
+func (s *CbExternalSource) SearchContext(ctx context.Context, query string, searchType SearchType) (SearchResult, error) {
+	if !searchType.valid() {
+		return SearchResult{}, ErrUnsupported
+	}
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", s.parser.BaseUrl(), cbSearchPath), nil)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	request = request.WithContext(ctx)
+	q := request.URL.Query()
+	q.Add("form_search", strings.TrimSpace(query))
+	q.Add("form_searchtype", cbSearchTypes[searchType])
+	request.URL.RawQuery = q.Encode()
+	request.Header.Add("Cookie", fmt.Sprintf("PHPSESSID=%s", stringutil.RandString(26)))
+	var searchResult *SearchResult
+	err = s.withRetry(ctx, func() error {
+		response, err := s.do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+		searchResult, err = s.parser.Search(response.Body, searchType)
+		return withUrl(err, request.URL.String())
+	})
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return *searchResult, nil
+}
+
+// Searches the entities of the type on the provided query.
+func (s *GcdExternalSource) Search(query string, searchType SearchType) (SearchResult, error) {
+	return s.SearchContext(context.Background(), query, searchType)
+}
+
+// Searches the entities of the type on the provided query. The request is canceled when the context is done.
+// GCD doesn't have story arcs, so searching them returns `ErrUnsupported` without sending a request.
+func (s *GcdExternalSource) SearchContext(ctx context.Context, query string, searchType SearchType) (SearchResult, error) {
+	if !searchType.valid() || gcdSearchObjects[searchType] == "" {
+		return SearchResult{}, ErrUnsupported
+	}
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", s.parser.BaseUrl(), gcdSearchPath), nil)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	q := request.URL.Query()
+	q.Add("q", strings.TrimSpace(query))
+	q.Add("search_object", gcdSearchObjects[searchType])
+	request.URL.RawQuery = q.Encode()
+	response, err := s.do(ctx, request)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	defer response.Body.Close()
+	searchResult, err := s.parser.Search(response.Body, searchType)
+	if err != nil {
+		return SearchResult{}, withUrl(err, request.URL.String())
+	}
+	return *searchResult, nil
+}
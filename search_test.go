@@ -0,0 +1,151 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestCbParser_Search(t *testing.T) {
+	parser := CbParser{}
+	// The title, creator and story arc results are synthetic, made from the real character results in cyclops/search.html.
+	file, err := os.Open("./testdata/cb_search_title.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	result, err := parser.Search(file, SearchTitle)
+	assert.Nil(t, err)
+	assert.Equal(t, "astonishing x-men", result.Query)
+	assert.Equal(t, SearchTitle, result.Type)
+	assert.Equal(t, []SeriesLink{
+		{Url: "http://comicbookdb.com/title.php?ID=11105", Name: "Astonishing X-Men", StartYear: 1995, Publisher: "Marvel"},
+		{Url: "http://comicbookdb.com/title.php?ID=439", Name: "Astonishing X-Men", StartYear: 2004, Publisher: "Marvel"},
+		{Url: "http://comicbookdb.com/title.php?ID=27433", Name: "Astonishing X-Men: Ghost Boxes", StartYear: 2008, Publisher: "Marvel"},
+		{Url: "http://comicbookdb.com/title.php?ID=41201", Name: "Astonishing X-Men Annual", StartYear: 2012},
+	}, result.Titles)
+	assert.Len(t, result.Characters, 0)
+
+	file2, err := os.Open("./testdata/cb_search_creator.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	result, err = parser.Search(file2, SearchCreator)
+	assert.Nil(t, err)
+	assert.Equal(t, []CreatorLink{
+		{Url: "http://comicbookdb.com/creator.php?ID=1339", Name: "Joss Whedon"},
+		{Url: "http://comicbookdb.com/creator.php?ID=22418", Name: "Zack Whedon"},
+	}, result.Creators)
+
+	file3, err := os.Open("./testdata/cb_search_storyarc.html")
+	defer file3.Close()
+	assert.Nil(t, err)
+	result, err = parser.Search(file3, SearchStoryArc)
+	assert.Nil(t, err)
+	assert.Equal(t, []StoryArcLink{
+		{Url: "http://comicbookdb.com/storyarc.php?ID=2002", Name: "Unstoppable", Publisher: "Marvel"},
+		{Url: "http://comicbookdb.com/storyarc.php?ID=7301", Name: "The Unstoppable Wasp"},
+	}, result.StoryArcs)
+
+	// Only the links for the search type are results.
+	file4, err := os.Open("./testdata/cyclops/search.html")
+	defer file4.Close()
+	assert.Nil(t, err)
+	result, err = parser.Search(file4, SearchCharacter)
+	assert.Nil(t, err)
+	assert.Equal(t, "cyclops", result.Query)
+	assert.Len(t, result.Characters, 46)
+	assert.Equal(t, "Cyclops (DC)(Post Flashpoint)", result.Characters[0].Name)
+
+	_, err = parser.Search(file4, SearchType(10))
+	assert.Equal(t, ErrUnsupported, err)
+}
+
+func TestGcdParser_Search(t *testing.T) {
+	parser := GcdParser{}
+	file, err := os.Open("./testdata/gcd/search_series.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	result, err := parser.Search(file, SearchTitle)
+	assert.Nil(t, err)
+	assert.Equal(t, "astonishing x-men", result.Query)
+	assert.Equal(t, []SeriesLink{
+		{Url: "https://www.comics.org/series/4364/", Name: "Astonishing X-Men", StartYear: 1995, Publisher: "Marvel"},
+		{Url: "https://www.comics.org/series/11105/", Name: "Astonishing X-Men", StartYear: 2004, Publisher: "Marvel"},
+	}, result.Titles)
+
+	file2, err := os.Open("./testdata/gcd/search.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	result, err = parser.Search(file2, SearchCharacter)
+	assert.Nil(t, err)
+	assert.Len(t, result.Characters, 3)
+	assert.Equal(t, "Cyclops [Greek mythology]", result.Characters[1].Name)
+
+	_, err = parser.Search(file2, SearchStoryArc)
+	assert.Equal(t, ErrUnsupported, err)
+}
+
+func TestCbExternalSource_Search(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search.php", r.URL.Path)
+		assert.Equal(t, "whedon", r.URL.Query().Get("form_search"))
+		assert.Equal(t, "Creator", r.URL.Query().Get("form_searchtype"))
+		file, err := os.Open("./testdata/cb_search_creator.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	result, err := externalSource.Search(" whedon ", SearchCreator)
+	assert.Nil(t, err)
+	assert.Len(t, result.Creators, 2)
+	assert.Equal(t, ts.URL+"/creator.php?ID=1339", result.Creators[0].Url)
+}
+
+func TestGcdExternalSource_Search(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/searchNew/", r.URL.Path)
+		assert.Equal(t, "series", r.URL.Query().Get("search_object"))
+		file, err := os.Open("./testdata/gcd/search_series.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := GcdExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewGcdParser(ts.URL),
+		config:     &GcdExternalSourceConfig{},
+	}
+	result, err := externalSource.Search("astonishing x-men", SearchTitle)
+	assert.Nil(t, err)
+	assert.Len(t, result.Titles, 2)
+
+	// Story arcs are unsupported without sending a request.
+	_, err = externalSource.Search("unstoppable", SearchStoryArc)
+	assert.Equal(t, ErrUnsupported, err)
+}
+
+func TestSearchType_String(t *testing.T) {
+	assert.Equal(t, "story arc", SearchStoryArc.String())
+	assert.Equal(t, "character", SearchCharacter.String())
+	assert.Equal(t, "", SearchType(-1).String())
+}
@@ -1,21 +1,42 @@
 package externalissuesource
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"github.com/aimeelaplant/externalissuesource/httpcache"
 	"github.com/aimeelaplant/externalissuesource/internal/stringutil"
 	"net/http"
 	"strings"
 	"net"
 	"time"
+	"sort"
+	"sync"
 )
 
-const cbSearchPath = "/search.php"
+const (
+	cbSearchPath = "/search.php"
+	defaultIssueWorkers = 5
+	defaultRequestsPerSecond = 1
+	defaultBurst = 5
+)
 
 type ExternalSource interface {
 	Issue(url string) (*Issue, error)
 	CharacterPage(url string) (*CharacterPage, error)
 	SearchCharacter(query string) (CharacterSearchResult, error)
+	Search(query string, searchType SearchType) (SearchResult, error)
+	Character(url string) (*Character, error)
+	Series(url string) (*SeriesPage, error)
+	Publisher(url string) (*Publisher, error)
+	StoryArc(url string) (*StoryArc, error)
+	IssueContext(ctx context.Context, url string) (*Issue, error)
+	CharacterPageContext(ctx context.Context, url string) (*CharacterPage, error)
+	SearchCharacterContext(ctx context.Context, query string) (CharacterSearchResult, error)
+	SearchContext(ctx context.Context, query string, searchType SearchType) (SearchResult, error)
+	CharacterContext(ctx context.Context, url string) (*Character, error)
+	SeriesContext(ctx context.Context, url string) (*SeriesPage, error)
+	PublisherContext(ctx context.Context, url string) (*Publisher, error)
+	StoryArcContext(ctx context.Context, url string) (*StoryArc, error)
 }
 
 // Configuration options
@@ -23,6 +44,49 @@ type CbExternalSourceConfig struct {
 	SessionId string
 	CbOne string
 	CbTwo string
+	IssueWorkers int // The number of issues to fetch concurrently for a character. Default is 5 if not provided.
+	RequestsPerSecond float64 // The number of requests per second sent to each host. Default is 1 if not provided.
+	Burst int // The number of requests that can be sent to a host at once before being limited. Default is 5 if not provided.
+	Jitter time.Duration // The maximum random delay added before each request.
+	DisableRateLimit bool // Sends requests as fast as they're made. Only use this against your own servers.
+	Retry *RetryPolicy // How failed requests are retried. Default is `DefaultRetryPolicy` if not provided.
+	Cache httpcache.Cache // Stores the pages so they aren't downloaded again while they're fresh. Pages aren't cached if not provided.
+	CacheTTL *CbCacheTTL // How long each type of page stays fresh in the cache. Default is `DefaultCbCacheTTL` if not provided.
+	OnSaleEstimator OnSaleEstimator // Estimates the on-sale dates from the cover dates. Default is `DefaultOnSaleEstimator` if not provided.
+	Logger Logger // Logs the warnings from parsing issues. Nothing is logged if not provided.
+}
+
+// Gets the number of workers for fetching a character's issues.
+func (c *CbExternalSourceConfig) issueWorkers() int {
+	if c.IssueWorkers <= 0 {
+		return defaultIssueWorkers
+	}
+	return c.IssueWorkers
+}
+
+// Gets the retry policy from the config.
+func (c *CbExternalSourceConfig) retryPolicy() *RetryPolicy {
+	if c.Retry == nil {
+		policy := DefaultRetryPolicy
+		return &policy
+	}
+	return c.Retry
+}
+
+// The errors for each issue link that couldn't be fetched, keyed by the issue's URL.
+type IssueLinkErrors map[string]error
+
+func (e IssueLinkErrors) Error() string {
+	links := make([]string, 0, len(e))
+	for link := range e {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	messages := make([]string, 0, len(links))
+	for _, link := range links {
+		messages = append(messages, fmt.Sprintf("%s: %s", link, e[link]))
+	}
+	return fmt.Sprintf("failed to fetch %d issue(s): %s", len(e), strings.Join(messages, "; "))
 }
 
 type CbExternalSource struct {
@@ -30,16 +94,22 @@ type CbExternalSource struct {
 	parser     ExternalSourceParser
 	config     *CbExternalSourceConfig
 	isLoggedIn bool
+	retry      *RetryPolicy // Retries failed requests. Requests are only tried once if nil.
 }
 
-
 // Fetches an issue from the issue page.
 func (s *CbExternalSource) Issue(url string) (*Issue, error) {
+	return s.IssueContext(context.Background(), url)
+}
+
+// Fetches an issue from the issue page. The request is canceled when the context is done.
+func (s *CbExternalSource) IssueContext(ctx context.Context, url string) (*Issue, error) {
 	var issue *Issue
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 	req.Header.Add("PHPSESSID", s.config.SessionId)
 	req.AddCookie(&http.Cookie{
 		Name: "PHPSESSID",
@@ -53,67 +123,265 @@ func (s *CbExternalSource) Issue(url string) (*Issue, error) {
 		Name: "cbdb2",
 		Value: s.config.CbTwo,
 	})
-	resp, err := s.httpClient.Do(req)
+	err = s.withRetry(ctx, func() error {
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		issue, err = parseIssue(s.parser, resp.Body, url, s.config.Logger)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(fmt.Sprintf("got bad status code from URL %s: %d", url, resp.StatusCode))
+	return issue, nil
+}
+
+// Fetches the character page.
+func (s *CbExternalSource) CharacterPage(url string) (*CharacterPage, error) {
+	return s.CharacterPageContext(context.Background(), url)
+}
+
+// Fetches the character page. The request is canceled when the context is done.
+func (s *CbExternalSource) CharacterPageContext(ctx context.Context, url string) (*CharacterPage, error) {
+	var characterPage *CharacterPage
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
 	}
-	issue, err = s.parser.Issue(resp.Body)
+	req = req.WithContext(ctx)
+	err = s.withRetry(ctx, func() error {
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		characterPage, err = s.parser.Character(resp.Body)
+		return withUrl(err, url)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return issue, nil
+	return characterPage, nil
 }
 
-// Fetches the character page.
-func (s *CbExternalSource) CharacterPage(url string) (*CharacterPage, error) {
-	characterPage := new(CharacterPage)
-	resp, err := s.httpClient.Get(url)
+// Fetches the character page and then concurrently fetches each of the character's issues with
+// `IssueWorkers` workers. The issues keep the order of the links on the character page.
+// If any issues fail, the character is still returned with the issues that were fetched along
+// with an `IssueLinkErrors` error reporting each failed link.
+func (s *CbExternalSource) Character(url string) (*Character, error) {
+	return s.CharacterContext(context.Background(), url)
+}
+
+// Same as `Character`, but stops fetching the remaining issues as soon as the context is done
+// and returns the context's error.
+func (s *CbExternalSource) CharacterContext(ctx context.Context, url string) (*Character, error) {
+	return fetchCharacter(ctx, s, url, s.config.issueWorkers())
+}
+
+// Fetches the series (title) page with the links to all of its issues.
+func (s *CbExternalSource) Series(url string) (*SeriesPage, error) {
+	return s.SeriesContext(context.Background(), url)
+}
+
+// Fetches the series (title) page. The request is canceled when the context is done.
+func (s *CbExternalSource) SeriesContext(ctx context.Context, url string) (*SeriesPage, error) {
+	var seriesPage *SeriesPage
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(fmt.Sprintf("got bad status code from URL %s: %d", url, resp.StatusCode))
+	req = req.WithContext(ctx)
+	err = s.withRetry(ctx, func() error {
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		seriesPage, err = s.parser.Series(resp.Body)
+		return withUrl(err, url)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seriesPage, nil
+}
+
+// Fetches the publisher page with its imprints and the links to its series.
+func (s *CbExternalSource) Publisher(url string) (*Publisher, error) {
+	return s.PublisherContext(context.Background(), url)
+}
+
+// Fetches the publisher page. The request is canceled when the context is done.
+func (s *CbExternalSource) PublisherContext(ctx context.Context, url string) (*Publisher, error) {
+	var publisher *Publisher
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	err = s.withRetry(ctx, func() error {
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		publisher, err = s.parser.Publisher(resp.Body)
+		return withUrl(err, url)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// Fetches the story arc page with the links to its issues in reading order.
+func (s *CbExternalSource) StoryArc(url string) (*StoryArc, error) {
+	return s.StoryArcContext(context.Background(), url)
+}
+
+// Fetches the story arc page. The request is canceled when the context is done.
+func (s *CbExternalSource) StoryArcContext(ctx context.Context, url string) (*StoryArc, error) {
+	var storyArc *StoryArc
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	err = s.withRetry(ctx, func() error {
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		storyArc, err = s.parser.StoryArc(resp.Body)
+		return withUrl(err, url)
+	})
+	if err != nil {
+		return nil, err
 	}
-	characterPage, err = s.parser.Character(resp.Body)
-	return characterPage, err
+	return storyArc, nil
 }
 
 // Performs a search on the provided query and returns the search result for found characters.
 func (s *CbExternalSource) SearchCharacter(query string) (CharacterSearchResult, error) {
+	return s.SearchCharacterContext(context.Background(), query)
+}
+
+// Performs a search on the provided query and returns the search result for found characters.
+// The request is canceled when the context is done.
+func (s *CbExternalSource) SearchCharacterContext(ctx context.Context, query string) (CharacterSearchResult, error) {
 	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s", s.parser.BaseUrl(), cbSearchPath), nil)
 	if err != nil {
 		return CharacterSearchResult{}, err
 	}
+	request = request.WithContext(ctx)
 	q := request.URL.Query()
 	q.Add("form_search", strings.TrimSpace(query))
 	q.Add("form_searchtype", "Character")
 	request.URL.RawQuery = q.Encode()
 	request.Header.Add("Cookie", fmt.Sprintf("PHPSESSID=%s", stringutil.RandString(26)))
-	response, err := s.httpClient.Do(request)
+	var characterSearchResult *CharacterSearchResult
+	err = s.withRetry(ctx, func() error {
+		response, err := s.do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+		characterSearchResult, err = s.parser.CharacterSearch(response.Body)
+		return withUrl(err, request.URL.String())
+	})
 	if err != nil {
 		return CharacterSearchResult{}, err
 	}
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		return CharacterSearchResult{}, errors.New(fmt.Sprintf("got bad status code from search: %d", response.StatusCode))
+	return *characterSearchResult, nil
+}
+
+// Sends the request and checks the response status.
+// The caller is responsible for closing the body when there's no error.
+func (s *CbExternalSource) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
-	characterSearchResult, err := s.parser.CharacterSearch(response.Body)
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &HTTPStatusError{URL: req.URL.String(), StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// Calls fetch with the retry policy, or only once if there isn't one.
+func (s *CbExternalSource) withRetry(ctx context.Context, fetch func() error) error {
+	if s.retry == nil {
+		return fetch()
+	}
+	return s.retry.do(ctx, fetch)
+}
+
+// Fetches the character page from the source and fans out over its issue links with the given
+// number of workers. Shared by every `ExternalSource` so they aggregate characters the same way.
+func fetchCharacter(ctx context.Context, source ExternalSource, url string, workers int) (*Character, error) {
+	characterPage, err := source.CharacterPageContext(ctx, url)
 	if err != nil {
-		return CharacterSearchResult{}, err
+		return nil, err
 	}
-	return *characterSearchResult, nil
+	character := &Character{
+		Publisher:       characterPage.Publisher,
+		Name:            characterPage.Name,
+		Issues:          make([]Issue, 0, len(characterPage.IssueLinks)),
+		OtherIdentities: characterPage.OtherIdentities,
+	}
+	issues := make([]*Issue, len(characterPage.IssueLinks))
+	errs := make([]error, len(characterPage.IssueLinks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				issues[idx], errs[idx] = source.IssueContext(ctx, characterPage.IssueLinks[idx])
+			}
+		}()
+	}
+feed:
+	for idx := range characterPage.IssueLinks {
+		select {
+		case indexes <- idx:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	linkErrors := make(IssueLinkErrors)
+	for idx, issue := range issues {
+		if errs[idx] != nil {
+			linkErrors[characterPage.IssueLinks[idx]] = errs[idx]
+			continue
+		}
+		character.AddIssue(*issue)
+	}
+	if len(linkErrors) > 0 {
+		return character, linkErrors
+	}
+	return character, nil
 }
 
+// Creates the source. The client's transport is wrapped with the rate limiter and the cache from the config,
+// so the client that's passed in isn't modified.
 func NewCbExternalSource(httpClient *http.Client, config *CbExternalSourceConfig) ExternalSource {
 	return &CbExternalSource{
-		httpClient: httpClient,
-		parser:     &CbParser{},
+		httpClient: config.wrapClient(httpClient),
+		parser:     &CbParser{onSale: config.OnSaleEstimator},
 		config:     config,
+		retry:      config.retryPolicy(),
 	}
 }
 
@@ -1,7 +1,9 @@
 package externalissuesource
 
 import (
+	"context"
 	"fmt"
+	"github.com/aimeelaplant/externalissuesource/httpreplay"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
@@ -9,6 +11,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
+	"github.com/aimeelaplant/externalissuesource/internal/ratelimit"
 )
 
 var config = &CbExternalSourceConfig{
@@ -143,6 +147,208 @@ func TestCbExternalSource_CharacterPage(t *testing.T) {
 	assert.Equal(t, "Marvel", character.Publisher)
 }
 
+func TestCbExternalSource_Character_Cyclops(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := "./testdata/cyclops/detail.html"
+		if r.URL.Path == "/issue.php" {
+			path = fmt.Sprintf("./testdata/cyclops/issues/%s.html", r.URL.Query().Get("ID"))
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	parser := NewCbParser(ts.URL)
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     parser,
+		config:     &CbExternalSourceConfig{IssueWorkers: 2},
+	}
+	character, err := externalSource.Character(fmt.Sprintf("%s/character.php?ID=82321", ts.URL))
+	assert.Error(t, err)
+	linkErrors, ok := err.(IssueLinkErrors)
+	assert.True(t, ok)
+	assert.Len(t, linkErrors, 1)
+	assert.Contains(t, linkErrors, fmt.Sprintf("%s/issue.php?ID=bogus", ts.URL))
+	assert.Equal(t, "Cyclops", character.Name)
+	assert.Equal(t, "Marvel", character.Publisher)
+	assert.Len(t, character.OtherIdentities, 0)
+	assert.Len(t, character.Issues, 4)
+	assert.Equal(t, "338389", character.Issues[0].Id)
+	assert.Equal(t, "339874", character.Issues[1].Id)
+}
+
+func TestCbExternalSource_Series(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/title.php", r.URL.Path)
+		file, err := os.Open("./testdata/cb_title.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	series, err := externalSource.Series(fmt.Sprintf("%s/title.php?ID=439", ts.URL))
+	assert.Nil(t, err)
+	assert.Equal(t, "Astonishing X-Men", series.Name)
+	assert.Len(t, series.IssueLinks, 8)
+	assert.Equal(t, fmt.Sprintf("%s/issue.php?ID=103298", ts.URL), series.IssueLinks[5].Url)
+}
+
+func TestCbExternalSource_Publisher(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/publisher.php", r.URL.Path)
+		file, err := os.Open("./testdata/cb_publisher_imprint.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	publisher, err := externalSource.Publisher(fmt.Sprintf("%s/publisher.php?ID=26", ts.URL))
+	assert.Nil(t, err)
+	assert.Equal(t, "Epic Comics", publisher.Name)
+	assert.Equal(t, fmt.Sprintf("%s/publisher.php?ID=4", ts.URL), publisher.Parent.Url)
+	assert.Equal(t, fmt.Sprintf("%s/title.php?ID=4120", ts.URL), publisher.SeriesLinks[1].Url)
+}
+
+func TestCbExternalSource_StoryArc(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/storyarc.php", r.URL.Path)
+		file, err := os.Open("./testdata/cb_storyarc.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	storyArc, err := externalSource.StoryArc(fmt.Sprintf("%s/storyarc.php?ID=2002", ts.URL))
+	assert.Nil(t, err)
+	assert.Equal(t, "Unstoppable", storyArc.Name)
+	assert.Len(t, storyArc.IssueLinks, 7)
+	assert.Equal(t, fmt.Sprintf("%s/issue.php?ID=103298", ts.URL), storyArc.IssueLinks[3].Url)
+}
+
+func TestCbExternalSource_CharacterContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/issue.php" {
+			// Abandon the crawl on the first issue and hang until the client goes away.
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		file, err := os.Open("./testdata/cyclops/detail.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	externalSource := CbExternalSource{
+		httpClient: ts.Client(),
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{IssueWorkers: 1},
+	}
+	character, err := externalSource.CharacterContext(ctx, fmt.Sprintf("%s/character.php?ID=82321", ts.URL))
+	assert.Nil(t, character)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestCbExternalSource_IssueContext_Canceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent with a canceled context")
+	}))
+	defer ts.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cbdb := NewCbExternalSource(ts.Client(), &CbExternalSourceConfig{})
+	ish, err := cbdb.IssueContext(ctx, ts.URL)
+	assert.Nil(t, ish)
+	assert.Error(t, err)
+}
+
+func TestCbExternalSource_RetryAfter(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		file, err := os.Open("./testdata/cb_issue.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	client := ts.Client()
+	client.Transport = &rateLimitedTransport{base: client.Transport, limiter: ratelimit.New(1000, 10, 0)}
+	externalSource := CbExternalSource{
+		httpClient: client,
+		parser:     NewCbParser(ts.URL),
+		config:     &CbExternalSourceConfig{},
+	}
+	_, err := externalSource.Issue(ts.URL)
+	assert.Error(t, err)
+	start := time.Now()
+	ish, err := externalSource.Issue(ts.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "22", ish.Number)
+	assert.True(t, time.Since(start) >= 900*time.Millisecond)
+}
+
 func TestAdultIssue(t *testing.T) {
 	client := NewHttpClient()
 	cbdb := NewCbExternalSource(client, config)
@@ -177,3 +383,30 @@ func TestThereAre(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, HC, ish.Format)
 }
+
+// Replays testdata/cassettes/cyclops.json. Run with HTTPREPLAY_RECORD=1 to refresh the cassette from the live site.
+func TestCbExternalSource_Cassette(t *testing.T) {
+	mode := httpreplay.ModeFromEnv()
+	recorder, err := httpreplay.New("./testdata/cassettes/cyclops.json", mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	externalSource := NewCbExternalSource(&http.Client{Transport: recorder}, &CbExternalSourceConfig{
+		IssueWorkers:     2,
+		DisableRateLimit: mode == httpreplay.ModeReplay,
+	})
+
+	searchResult, err := externalSource.SearchCharacter("cyclops")
+	assert.Nil(t, err)
+	assert.Len(t, searchResult.Results, 46)
+
+	character, err := externalSource.Character("http://comicbookdb.com/character.php?ID=82321")
+	linkErrors, ok := err.(IssueLinkErrors)
+	assert.True(t, ok)
+	assert.Len(t, linkErrors, 1)
+	assert.Contains(t, linkErrors, "http://comicbookdb.com/issue.php?ID=bogus")
+	assert.Equal(t, "Cyclops", character.Name)
+	assert.Len(t, character.Issues, 4)
+	assert.Equal(t, "338389", character.Issues[0].Id)
+	assert.Nil(t, recorder.Save())
+}
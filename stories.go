@@ -0,0 +1,101 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"regexp"
+	"strings"
+)
+
+// One of the stories in an issue, such as a backup story in an annual or one of the issues collected in a TPB.
+type Story struct {
+	Title       string          // The title without quotes, such as `Beware My Power`.
+	PageCount   int             // The number of pages, or 0 if the source doesn't have it.
+	Credits     []Credit        // The creators of the story, with its index in `Issue.Stories` as their `StoryIndex`.
+	Characters  []CharacterLink // The characters that appear in the story, each listed once.
+	IsReprintOf *IssueLink      // The issue the story is reprinted from, or nil for a new story. Only the title is set when the source doesn't link to the issue.
+}
+
+// A note saying the story is reprinted, such as `Reprints Green Lantern #16` or `Reprinted from X-Men #1.`.
+var regReprintNote = regexp.MustCompile(`(?i)\breprint(?:s|ed)?(?:\s+from)?\s*:?\s+(.+)`)
+
+// Gets the stories from cb's "Multiple Stories in this Issue" box, where each story starts with its title in
+// `span.size13` followed by its creators, characters and notes. cb only lists stories for issues with more than one,
+// so issues with a single story don't have any. The credits are the ones in the issue's credits for each story.
+func (p *CbParser) stories(section *goquery.Selection, credits []Credit) []Story {
+	stories := make([]Story, 0)
+	label := ""
+	characterUrls := make(map[string]bool)
+	section.Find("span.size13, strong, a").Each(func(i int, s *goquery.Selection) {
+		switch {
+		case s.Is("span.size13"):
+			stories = append(stories, Story{
+				Title:      strings.Trim(strings.TrimSpace(s.Find("strong").Text()), "\""),
+				Credits:    make([]Credit, 0),
+				Characters: make([]CharacterLink, 0),
+			})
+			label = ""
+			characterUrls = make(map[string]bool)
+		case len(stories) == 0 || s.ParentFiltered("span.size13").Length() > 0:
+			return
+		case goquery.NodeName(s) == "strong":
+			label = strings.TrimSpace(s.Text())
+			if label == "Notes:" {
+				stories[len(stories)-1].IsReprintOf = p.reprintOf(s)
+			}
+		case label == "Characters:":
+			hrefValue, ex := s.Attr("href")
+			if !ex || !strings.HasPrefix(hrefValue, "character.php?ID=") {
+				return
+			}
+			characterLink := p.characterLink(s, hrefValue)
+			if !characterUrls[characterLink.Url] {
+				characterUrls[characterLink.Url] = true
+				stories[len(stories)-1].Characters = append(stories[len(stories)-1].Characters, characterLink)
+			}
+		}
+	})
+	for _, credit := range credits {
+		if credit.StoryIndex >= 0 && credit.StoryIndex < len(stories) {
+			stories[credit.StoryIndex].Credits = append(stories[credit.StoryIndex].Credits, credit)
+		}
+	}
+	return stories
+}
+
+// Gets the issue the story is reprinted from if the story's notes after the label say it's a reprint.
+// The notes run until the next label or story.
+func (p *CbParser) reprintOf(notesLabel *goquery.Selection) *IssueLink {
+	notes := ""
+	var issueLink *IssueLink
+	for node := notesLabel.Nodes[0].NextSibling; node != nil; node = node.NextSibling {
+		if node.Type == html.ElementNode && (node.Data == "strong" || node.Data == "hr" || node.Data == "span") {
+			break
+		}
+		s := goquery.NewDocumentFromNode(node).Selection
+		if hrefValue, ex := s.Attr("href"); ex && issueLink == nil && strings.HasPrefix(hrefValue, "issue.php?ID=") {
+			issueLink = &IssueLink{
+				Url:   fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue),
+				Id:    hrefValue[strings.Index(hrefValue, "=")+1:],
+				Title: strings.TrimSpace(s.Text()),
+			}
+		}
+		if node.Type == html.ElementNode && node.Data == "br" {
+			notes += "\n"
+		} else {
+			notes += s.Text()
+		}
+	}
+	for _, line := range strings.Split(notes, "\n") {
+		match := regReprintNote.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		if issueLink != nil {
+			return issueLink
+		}
+		return &IssueLink{Title: strings.TrimRight(strings.TrimSpace(match[1]), ".;,")}
+	}
+	return nil
+}
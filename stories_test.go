@@ -0,0 +1,71 @@
+package externalissuesource
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCbParser_Issue_Stories(t *testing.T) {
+	parser := CbParser{}
+	file, err := os.Open("./testdata/cb_issue_dec_jan.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Stories, 3)
+	assert.Equal(t, "Beware My Power", issue.Stories[0].Title)
+	assert.Len(t, issue.Stories[0].Credits, 5)
+	assert.Len(t, issue.Stories[0].Characters, 3)
+	assert.Equal(t, CharacterLink{Url: "http://comicbookdb.com/character.php?ID=35", Name: "Green Lantern (DC)(02 - Hal Jordan)"}, issue.Stories[0].Characters[0])
+	assert.Nil(t, issue.Stories[0].IsReprintOf)
+	assert.Equal(t, "What Can One Man Do?", issue.Stories[1].Title)
+	assert.Len(t, issue.Stories[1].Characters, 5)
+	for _, credit := range issue.Stories[1].Credits {
+		assert.Equal(t, 1, credit.StoryIndex)
+	}
+	// The notes name the reprinted issue without linking to it.
+	assert.Equal(t, "Earth's First Green Lantern", issue.Stories[2].Title)
+	assert.Equal(t, &IssueLink{Title: "Green Lantern #16"}, issue.Stories[2].IsReprintOf)
+
+	file2, err := os.Open("./testdata/cb_issue_no_reprint.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file2)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Stories, 12)
+	assert.Equal(t, "The Case of the Chemical Syndicate", issue.Stories[0].Title)
+	assert.Equal(t, "[untitled]", issue.Stories[1].Title)
+	assert.Len(t, issue.Stories[3].Characters, 0)
+	for _, story := range issue.Stories {
+		assert.Nil(t, story.IsReprintOf)
+	}
+
+	// Issues with a single story don't list it.
+	file3, err := os.Open("./testdata/cb_issue.html")
+	defer file3.Close()
+	assert.Nil(t, err)
+	issue, err = parser.Issue(file3)
+	assert.Nil(t, err)
+	assert.Len(t, issue.Stories, 0)
+}
+
+func TestCbParser_reprintOf(t *testing.T) {
+	parser := CbParser{}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<table class="noHeaderBox"><tr><td>
+		<span class="size13"><strong>"The Coming of Galactus"</strong></span><br>
+		<strong>Notes:</strong> Reprinted from <a href="issue.php?ID=1150">Fantastic Four (1961) #48</a>.<br>
+		<hr>
+		<span class="size13"><strong>"Galactus Hungers"</strong></span><br>
+		<strong>Notes:</strong> First story in the issue.<br>
+		<strong>Characters:</strong> <a href="character.php?ID=12">Galactus</a>
+	</td></tr></table>`))
+	assert.Nil(t, err)
+	stories := parser.stories(doc.Find("table.noHeaderBox"), []Credit{})
+	assert.Len(t, stories, 2)
+	assert.Equal(t, &IssueLink{Url: "http://comicbookdb.com/issue.php?ID=1150", Id: "1150", Title: "Fantastic Four (1961) #48"}, stories[0].IsReprintOf)
+	assert.Nil(t, stories[1].IsReprintOf)
+	assert.Equal(t, []CharacterLink{{Url: "http://comicbookdb.com/character.php?ID=12", Name: "Galactus"}}, stories[1].Characters)
+}
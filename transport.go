@@ -0,0 +1,154 @@
+package externalissuesource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/aimeelaplant/externalissuesource/httpcache"
+	"github.com/aimeelaplant/externalissuesource/internal/ratelimit"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The default time each type of cb page stays fresh in the cache.
+// Issues rarely change once they're entered, while characters, series, publishers and story arcs gain new issues and searches gain new characters.
+var DefaultCbCacheTTL = CbCacheTTL{
+	Issue:     7 * 24 * time.Hour,
+	Character: 24 * time.Hour,
+	Series:    24 * time.Hour,
+	Publisher: 24 * time.Hour,
+	StoryArc:  24 * time.Hour,
+	Search:    time.Hour,
+}
+
+// How long each type of cb page stays fresh in the cache. A TTL of 0 doesn't cache that type of page.
+type CbCacheTTL struct {
+	Issue     time.Duration
+	Character time.Duration
+	Series    time.Duration
+	Publisher time.Duration
+	StoryArc  time.Duration
+	Search    time.Duration
+}
+
+// Gets the TTL for the request by the page it's for. Other pages aren't cached.
+func (c *CbCacheTTL) ttl(req *http.Request) time.Duration {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/issue.php"):
+		return c.Issue
+	case strings.HasSuffix(req.URL.Path, "/character.php"):
+		return c.Character
+	case strings.HasSuffix(req.URL.Path, "/title.php"):
+		return c.Series
+	case strings.HasSuffix(req.URL.Path, "/publisher.php"):
+		return c.Publisher
+	case strings.HasSuffix(req.URL.Path, "/storyarc.php"):
+		return c.StoryArc
+	case strings.HasSuffix(req.URL.Path, cbSearchPath):
+		return c.Search
+	}
+	return 0
+}
+
+// The cookies that decide what cb shows on issue pages, such as the issues hidden from visitors who aren't logged in.
+var cbSessionCookies = []string{"PHPSESSID", "cbdb1", "cbdb2"}
+
+// Gets the cache key for the request. Issue pages depend on the session, so their key has a hash of the
+// session cookies and a page fetched without them is never served to a logged-in request.
+func cbCacheKey(req *http.Request) string {
+	key := req.URL.String()
+	if !strings.HasSuffix(req.URL.Path, "/issue.php") {
+		return key
+	}
+	hash := sha256.New()
+	for _, name := range cbSessionCookies {
+		value := ""
+		if cookie, err := req.Cookie(name); err == nil {
+			value = cookie.Value
+		}
+		hash.Write([]byte(name + "=" + value + ";"))
+	}
+	return key + "#session=" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// Whether the cb page is worth caching. cb serves its `mysql_connect()` outage page as a 200,
+// so it's rejected and the retry after an `ErrConnection` downloads the page again.
+func cbCacheValid(resp *http.Response, body []byte) bool {
+	return !bytes.Contains(body, []byte("mysql_connect()"))
+}
+
+// Creates the per-host rate limiter from the config, or nil if rate limiting is disabled.
+func (c *CbExternalSourceConfig) limiter() *ratelimit.Limiter {
+	if c.DisableRateLimit {
+		return nil
+	}
+	requestsPerSecond := c.RequestsPerSecond
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = defaultRequestsPerSecond
+	}
+	burst := c.Burst
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+	return ratelimit.New(requestsPerSecond, burst, c.Jitter)
+}
+
+// Copies the client with its transport wrapped by the rate limiter and then the cache, so pages
+// served from the cache don't wait for the rate limiter.
+func (c *CbExternalSourceConfig) wrapClient(client *http.Client) *http.Client {
+	wrapped := *client
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if limiter := c.limiter(); limiter != nil {
+		transport = &rateLimitedTransport{base: transport, limiter: limiter}
+	}
+	if c.Cache != nil {
+		cacheTTL := c.CacheTTL
+		if cacheTTL == nil {
+			cacheTTL = &DefaultCbCacheTTL
+		}
+		transport = &httpcache.Transport{Cache: c.Cache, TTL: cacheTTL.ttl, Key: cbCacheKey, Valid: cbCacheValid, Base: transport}
+	}
+	wrapped.Transport = transport
+	return &wrapped
+}
+
+// Waits for the per-host rate limiter before sending each request. If the host asks to back off
+// with `Retry-After`, later requests to the host wait until then.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *ratelimit.Limiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if until, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		t.limiter.BlockUntil(req.URL.Host, until)
+	}
+	return resp, nil
+}
+
+// Parses the value of a `Retry-After` header, which is either a number of seconds or an HTTP date,
+// into the time when requests can resume. Returns false if there's no value or it can't be parsed.
+func retryAfter(value string, now time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,141 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/aimeelaplant/externalissuesource/httpcache"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2018, time.June, 1, 12, 0, 0, 0, time.UTC)
+	until, ok := retryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(2*time.Minute), until)
+
+	until, ok = retryAfter("Fri, 01 Jun 2018 12:05:00 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(5*time.Minute), until.UTC())
+
+	_, ok = retryAfter("", now)
+	assert.False(t, ok)
+	_, ok = retryAfter("soon", now)
+	assert.False(t, ok)
+}
+
+func TestCbExternalSourceConfig_Limiter(t *testing.T) {
+	assert.NotNil(t, (&CbExternalSourceConfig{}).limiter())
+	assert.Nil(t, (&CbExternalSourceConfig{DisableRateLimit: true}).limiter())
+}
+
+func TestCbCacheTTL(t *testing.T) {
+	ttl := &CbCacheTTL{Issue: 3 * time.Hour, Character: 2 * time.Hour, Series: 4 * time.Hour, Publisher: 5 * time.Hour, StoryArc: 6 * time.Hour, Search: time.Hour}
+	for url, expected := range map[string]time.Duration{
+		"http://comicbookdb.com/issue.php?ID=283781":                      3 * time.Hour,
+		"http://comicbookdb.com/character.php?ID=82321":                   2 * time.Hour,
+		"http://comicbookdb.com/search.php?form_search=cyclops":           time.Hour,
+		"http://comicbookdb.com/title.php?ID=1234":                        4 * time.Hour,
+		"http://comicbookdb.com/publisher.php?ID=4":                       5 * time.Hour,
+		"http://comicbookdb.com/storyarc.php?ID=2002":                     6 * time.Hour,
+		"http://comicbookdb.com/creator.php?ID=59135":                     0,
+		"http://comicbookdb.com/graphics/comic_graphics/1/283/134434.jpg": 0,
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, ttl.ttl(req), url)
+	}
+}
+
+func TestNewCbExternalSource_Cache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cb-cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cache, err := httpcache.NewDiskCache(dir)
+	assert.Nil(t, err)
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		file, err := os.Open("./testdata/cyclops/detail.html")
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		bytes, err := ioutil.ReadAll(file)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	client := ts.Client()
+	externalSource := NewCbExternalSource(client, &CbExternalSourceConfig{Cache: cache, DisableRateLimit: true})
+	externalSource.(*CbExternalSource).parser = NewCbParser(ts.URL)
+
+	for i := 0; i < 2; i++ {
+		character, err := externalSource.CharacterPage(fmt.Sprintf("%s/character.php?ID=82321", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, "Cyclops", character.Name)
+	}
+	assert.Equal(t, 1, requests)
+	_, isCached := client.Transport.(*httpcache.Transport)
+	assert.False(t, isCached)
+}
+
+func TestCbCacheKey(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://comicbookdb.com/issue.php?ID=283781", nil)
+	assert.Nil(t, err)
+	anonymous := cbCacheKey(req)
+	req.AddCookie(&http.Cookie{Name: "PHPSESSID", Value: "session"})
+	req.AddCookie(&http.Cookie{Name: "cbdb1", Value: "272498"})
+	loggedIn := cbCacheKey(req)
+	assert.NotEqual(t, anonymous, loggedIn)
+	assert.Contains(t, loggedIn, "http://comicbookdb.com/issue.php?ID=283781#session=")
+	assert.NotContains(t, loggedIn, "272498")
+
+	// Only issue pages depend on the session.
+	req, err = http.NewRequest(http.MethodGet, "http://comicbookdb.com/character.php?ID=82321", nil)
+	assert.Nil(t, err)
+	req.AddCookie(&http.Cookie{Name: "PHPSESSID", Value: "session"})
+	assert.Equal(t, "http://comicbookdb.com/character.php?ID=82321", cbCacheKey(req))
+}
+
+func TestNewCbExternalSource_CacheConnectionError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cb-cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cache, err := httpcache.NewDiskCache(dir)
+	assert.Nil(t, err)
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fixture := "./testdata/cb_issue.html"
+		if requests == 1 {
+			// cb serves its outage page as a 200.
+			fixture = "./testdata/cb_error.html"
+		}
+		bytes, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(bytes)
+	}))
+	defer ts.Close()
+	retry := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	externalSource := NewCbExternalSource(ts.Client(), &CbExternalSourceConfig{Cache: cache, DisableRateLimit: true, Retry: &retry})
+	externalSource.(*CbExternalSource).parser = NewCbParser(ts.URL)
+
+	// The retry gets the real page instead of the outage page from the cache, and the real page is cached.
+	for i := 0; i < 2; i++ {
+		issue, err := externalSource.Issue(fmt.Sprintf("%s/issue.php?ID=103298", ts.URL))
+		assert.Nil(t, err)
+		assert.Equal(t, "22", issue.Number)
+	}
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,96 @@
+package externalissuesource
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The market an edition of an issue was sold in.
+type Edition int
+
+// Editions of an issue.
+const (
+	EditionUnknown   Edition = iota // The source doesn't say which edition it is.
+	EditionDirect                   // Sold to comic shops through the direct market, such as "(Direct Edition)".
+	EditionNewsstand                // Sold on newsstands, such as "(Newsstand Edition)".
+)
+
+var editionNames = [...]string{"", "direct", "newsstand"}
+
+var (
+	// The story title in quotes at the start of cb's subheadline, such as `"Deathstar, Rising!"`.
+	regVariantTitle        = regexp.MustCompile(`"[^"]*"`)
+	regVariantCover        = regexp.MustCompile(`\bCover ([A-Za-z])\b`)
+	regVariantPrinting     = regexp.MustCompile(`(?i)\b(\d+)(?:st|nd|rd|th) Printing\b`)
+	regVariantPrintingWord = regexp.MustCompile(`(?i)\b(first|second|third|fourth|fifth|sixth|seventh|eighth|ninth|tenth) Printing\b`)
+	regVariantRatio        = regexp.MustCompile(`(?i)\b1\s*(?::|in)\s*(\d+)\b`)
+	regVariantIncentive    = regexp.MustCompile(`(?i)\b(?:incentive|ratio)\b`)
+	regVariantExclusive    = regexp.MustCompile(`(?i)\bexclusive\b`)
+	regVariantEdition      = regexp.MustCompile(`(?i)\b(direct|newsstand)(?:\s+(?:edition|market))?\b`)
+	regVariantDirectorsCut = regexp.MustCompile(`(?i)\bdirector'?s'? cut\b`)
+	regVariantLabel        = regexp.MustCompile(`(?i)\bvariant\b`)
+)
+
+// The printings spelled out as words, such as "Second Printing".
+var printingWords = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+	"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+}
+
+// Gets the name of the edition, such as `newsstand`, or an empty string for `EditionUnknown`.
+func (e Edition) String() string {
+	if e < 0 || int(e) >= len(editionNames) {
+		return editionNames[EditionUnknown]
+	}
+	return editionNames[e]
+}
+
+// What kind of variant, printing or edition an issue is.
+type VariantInfo struct {
+	CoverLetter       string  // The letter of the cover, such as `B` for "Cover B".
+	Printing          int     // The printing, such as `2` for "2nd Printing". 0 if the source doesn't say.
+	Ratio             int     // The number of regular copies a retailer orders to get one copy, such as `25` for "1:25". 0 if it's not a ratio variant.
+	Incentive         bool    // Whether it's an incentive variant, which includes ratio variants.
+	RetailerExclusive bool    // Whether it's exclusive to a retailer or convention, such as "(Midtown Comics Exclusive)".
+	Edition           Edition // Whether it's the direct or newsstand edition.
+	DirectorsCut      bool    // Whether it's a "Director's Cut" edition.
+	Labeled           bool    // Whether the source calls it a variant, such as "(Sketch Variant)".
+}
+
+// Whether it's a variant cover or a later printing of another issue.
+// The direct and newsstand editions and director's cuts aren't variants on their own.
+func (v VariantInfo) IsVariant() bool {
+	return v.CoverLetter != "" || v.Printing > 1 || v.Ratio > 0 || v.Incentive || v.RetailerExclusive || v.Labeled
+}
+
+// Parses the variant info from an issue's subheadline, such as `"Deathstar, Rising!" (Direct Edition)`,
+// or from the name of a variant, such as `Cover B` or `1:25 Incentive Variant`.
+// The story title in quotes is ignored so a title like "Variant" doesn't make the issue a variant.
+func ParseVariantInfo(text string) VariantInfo {
+	text = regVariantTitle.ReplaceAllString(text, "")
+	info := VariantInfo{}
+	if match := regVariantCover.FindStringSubmatch(text); match != nil {
+		info.CoverLetter = strings.ToUpper(match[1])
+	}
+	if match := regVariantPrinting.FindStringSubmatch(text); match != nil {
+		info.Printing, _ = strconv.Atoi(match[1])
+	} else if match := regVariantPrintingWord.FindStringSubmatch(text); match != nil {
+		info.Printing = printingWords[strings.ToLower(match[1])]
+	}
+	if match := regVariantRatio.FindStringSubmatch(text); match != nil {
+		info.Ratio, _ = strconv.Atoi(match[1])
+	}
+	info.Incentive = info.Ratio > 0 || regVariantIncentive.MatchString(text)
+	info.RetailerExclusive = regVariantExclusive.MatchString(text)
+	if match := regVariantEdition.FindStringSubmatch(text); match != nil {
+		if strings.ToLower(match[1]) == "newsstand" {
+			info.Edition = EditionNewsstand
+		} else {
+			info.Edition = EditionDirect
+		}
+	}
+	info.DirectorsCut = regVariantDirectorsCut.MatchString(text)
+	info.Labeled = regVariantLabel.MatchString(text)
+	return info
+}
@@ -0,0 +1,64 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestParseVariantInfo(t *testing.T) {
+	testCases := []struct {
+		name      string
+		text      string
+		expected  VariantInfo
+		isVariant bool
+	}{
+		{name: "original", text: `"Unstoppable, Part 4"`, expected: VariantInfo{}},
+		{name: "cover letter", text: `"Unstoppable, Part 4" (Cover B)`, expected: VariantInfo{CoverLetter: "B"}, isVariant: true},
+		{name: "lowercase cover letter", text: "Cover c", expected: VariantInfo{CoverLetter: "C"}, isVariant: true},
+		{name: "named cover", text: "Wolverine Cover", expected: VariantInfo{}},
+		{name: "2nd printing", text: `"Batman" (2nd Printing)`, expected: VariantInfo{Printing: 2}, isVariant: true},
+		{name: "3rd printing", text: "(3rd Printing)", expected: VariantInfo{Printing: 3}, isVariant: true},
+		{name: "printing in words", text: "(Second Printing)", expected: VariantInfo{Printing: 2}, isVariant: true},
+		{name: "first printing", text: "(First Printing)", expected: VariantInfo{Printing: 1}},
+		{name: "ratio", text: "(1:25 Variant)", expected: VariantInfo{Ratio: 25, Incentive: true, Labeled: true}, isVariant: true},
+		{name: "ratio in words", text: "(1 in 50 Cover)", expected: VariantInfo{Ratio: 50, Incentive: true}, isVariant: true},
+		{name: "incentive", text: "(Incentive Sketch Cover)", expected: VariantInfo{Incentive: true}, isVariant: true},
+		{name: "retailer exclusive", text: "(Midtown Comics Exclusive Variant)", expected: VariantInfo{RetailerExclusive: true, Labeled: true}, isVariant: true},
+		{name: "direct edition", text: `"Deathstar, Rising!" (Direct Edition)`, expected: VariantInfo{Edition: EditionDirect}},
+		{name: "direct", text: "Direct", expected: VariantInfo{Edition: EditionDirect}},
+		{name: "newsstand edition", text: "(Newsstand Edition)", expected: VariantInfo{Edition: EditionNewsstand}},
+		{name: "newsstand", text: "Newsstand", expected: VariantInfo{Edition: EditionNewsstand}},
+		{name: "director's cut", text: "(Director's Cut)", expected: VariantInfo{DirectorsCut: true}},
+		{name: "directors cut", text: "Directors Cut", expected: VariantInfo{DirectorsCut: true}},
+		{name: "variant", text: "(Variant)", expected: VariantInfo{Labeled: true}, isVariant: true},
+		{name: "variant in title", text: `"The Variant Direct Edition"`, expected: VariantInfo{}},
+		{name: "cover art", text: "(Cover Art by Alex Ross)", expected: VariantInfo{}},
+		{name: "combined", text: "(Cover B 2nd Printing Newsstand Edition)", expected: VariantInfo{CoverLetter: "B", Printing: 2, Edition: EditionNewsstand}, isVariant: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			info := ParseVariantInfo(testCase.text)
+			assert.Equal(t, testCase.expected, info)
+			assert.Equal(t, testCase.isVariant, info.IsVariant())
+		})
+	}
+}
+
+func TestEdition_String(t *testing.T) {
+	assert.Equal(t, "", EditionUnknown.String())
+	assert.Equal(t, "direct", EditionDirect.String())
+	assert.Equal(t, "newsstand", EditionNewsstand.String())
+	assert.Equal(t, "", Edition(10).String())
+}
+
+func TestCbParser_Issue_VariantInfo(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue_reprint.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, VariantInfo{Edition: EditionDirect}, issue.VariantInfo)
+	assert.False(t, issue.IsVariant)
+}
@@ -0,0 +1,135 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+// The labels cb puts before the links to the other versions of an issue.
+const (
+	cbVersionOfLabel = "This is a version of the following issue:"
+	cbVersionsLabel  = "There are other versions of this issue in the database:"
+)
+
+// A canonical issue with its variants, printings and other versions, so they're counted once.
+type IssueCluster struct {
+	Id        string  // unique identifier for the canonical issue.
+	Canonical *Issue  // The canonical issue, or nil if only its versions were in the issues that were grouped.
+	Versions  []Issue // The variants, printings and other versions of the canonical issue in the order they were grouped.
+}
+
+// Groups the issues into clusters of a canonical issue and its versions, in the order each cluster's first issue appears.
+// Versions are found with `OriginalIssueId` and the canonical issue's `Versions`. A variant without either joins the only
+// issue that isn't a variant with the same series and number, if there is one.
+func GroupVersions(issues []Issue) []IssueCluster {
+	byId := make(map[string]int, len(issues))
+	for idx, issue := range issues {
+		if issue.Id != "" {
+			byId[issue.Id] = idx
+		}
+	}
+	// The ID of the issue each issue is a version of.
+	originalIds := make([]string, len(issues))
+	for idx, issue := range issues {
+		originalIds[idx] = issue.OriginalIssueId
+	}
+	for _, issue := range issues {
+		for _, version := range issue.Versions {
+			if idx, ok := byId[version.Id]; ok && originalIds[idx] == "" && version.Id != issue.Id {
+				originalIds[idx] = issue.Id
+			}
+		}
+	}
+	originals := make(map[string][]int)
+	for idx, issue := range issues {
+		if !issue.IsVariant {
+			key := fmt.Sprintf("%s#%s", issue.SeriesId, issue.Number)
+			originals[key] = append(originals[key], idx)
+		}
+	}
+	for idx, issue := range issues {
+		if originalIds[idx] != "" || !issue.IsVariant || issue.SeriesId == "" {
+			continue
+		}
+		if candidates := originals[fmt.Sprintf("%s#%s", issue.SeriesId, issue.Number)]; len(candidates) == 1 {
+			originalIds[idx] = issues[candidates[0]].Id
+		}
+	}
+
+	clusters := make([]IssueCluster, 0)
+	clusterIndexes := make(map[string]int)
+	for idx := range issues {
+		canonicalId := canonicalIssueId(issues, byId, originalIds, idx)
+		clusterIndex, ok := clusterIndexes[canonicalId]
+		if !ok || canonicalId == "" {
+			clusterIndex = len(clusters)
+			clusters = append(clusters, IssueCluster{Id: canonicalId, Versions: make([]Issue, 0)})
+			clusterIndexes[canonicalId] = clusterIndex
+		}
+		if issues[idx].Id == canonicalId {
+			canonical := issues[idx]
+			clusters[clusterIndex].Canonical = &canonical
+		} else {
+			clusters[clusterIndex].Versions = append(clusters[clusterIndex].Versions, issues[idx])
+		}
+	}
+	return clusters
+}
+
+// Follows the issue's original issues until the one that isn't a version of another issue, or isn't in the issues.
+// Issues that are versions of each other are grouped under the first of them in the issues.
+func canonicalIssueId(issues []Issue, byId map[string]int, originalIds []string, idx int) string {
+	path := make([]int, 0)
+	seen := make(map[int]int)
+	for originalIds[idx] != "" {
+		if start, ok := seen[idx]; ok {
+			first := idx
+			for _, cycleIdx := range path[start:] {
+				if cycleIdx < first {
+					first = cycleIdx
+				}
+			}
+			return issues[first].Id
+		}
+		seen[idx] = len(path)
+		path = append(path, idx)
+		originalIdx, ok := byId[originalIds[idx]]
+		if !ok {
+			return originalIds[idx]
+		}
+		idx = originalIdx
+	}
+	return issues[idx].Id
+}
+
+// Gets the issue this one is a version of and the other versions of it from cb's version lists in the section.
+func (p *CbParser) versions(section *goquery.Selection) (*IssueLink, []IssueLink) {
+	var versionOf *IssueLink
+	versions := make([]IssueLink, 0)
+	label := ""
+	section.Find("strong, a").Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "strong" {
+			label = strings.TrimSpace(s.Text())
+			return
+		}
+		hrefValue, ex := s.Attr("href")
+		if !ex || !strings.HasPrefix(hrefValue, "issue.php?ID=") {
+			return
+		}
+		issueLink := IssueLink{
+			Url:   fmt.Sprintf("%s/%s", p.BaseUrl(), hrefValue),
+			Id:    hrefValue[strings.Index(hrefValue, "=")+1:],
+			Title: strings.TrimSpace(s.Text()),
+		}
+		switch label {
+		case cbVersionOfLabel:
+			if versionOf == nil {
+				versionOf = &issueLink
+			}
+		case cbVersionsLabel:
+			versions = append(versions, issueLink)
+		}
+	})
+	return versionOf, versions
+}
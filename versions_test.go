@@ -0,0 +1,93 @@
+package externalissuesource
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Gets the IDs of the clusters' canonical issues and versions, such as `1: 2 3`.
+func clusterIds(clusters []IssueCluster) []string {
+	ids := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		id := cluster.Id
+		if cluster.Canonical == nil {
+			id = "(" + id + ")"
+		}
+		id += ":"
+		for _, version := range cluster.Versions {
+			id += " " + version.Id
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestGroupVersions(t *testing.T) {
+	for name, test := range map[string]struct {
+		issues   []Issue
+		expected []string
+	}{
+		"original issue id": {
+			issues: []Issue{
+				{Id: "1", SeriesId: "10", Number: "1"},
+				{Id: "2", SeriesId: "10", Number: "1", IsVariant: true, OriginalIssueId: "1"},
+				{Id: "3", SeriesId: "10", Number: "2"},
+			},
+			expected: []string{"1: 2", "3:"},
+		},
+		"variant before the original": {
+			issues: []Issue{
+				{Id: "2", SeriesId: "10", Number: "1", IsVariant: true, OriginalIssueId: "1"},
+				{Id: "1", SeriesId: "10", Number: "1"},
+			},
+			expected: []string{"1: 2"},
+		},
+		"original not grouped": {
+			issues: []Issue{
+				{Id: "2", SeriesId: "10", Number: "1", IsVariant: true, OriginalIssueId: "1"},
+				{Id: "3", SeriesId: "10", Number: "1", IsVariant: true, OriginalIssueId: "1"},
+			},
+			expected: []string{"(1): 2 3"},
+		},
+		"versions of the original": {
+			issues: []Issue{
+				{Id: "1", SeriesId: "10", Number: "1", Versions: []IssueLink{{Id: "2"}, {Id: "4"}}},
+				{Id: "2", SeriesId: "10", Number: "1 (2nd Printing)"},
+			},
+			expected: []string{"1: 2"},
+		},
+		"version of a version": {
+			issues: []Issue{
+				{Id: "1", SeriesId: "10", Number: "1"},
+				{Id: "2", OriginalIssueId: "1"},
+				{Id: "3", OriginalIssueId: "2"},
+			},
+			expected: []string{"1: 2 3"},
+		},
+		"variant with the same series and number": {
+			issues: []Issue{
+				{Id: "1", SeriesId: "10", Number: "22"},
+				{Id: "2", SeriesId: "10", Number: "22", IsVariant: true},
+				{Id: "3", SeriesId: "11", Number: "22", IsVariant: true},
+			},
+			expected: []string{"1: 2", "3:"},
+		},
+		"variant with more than one possible original": {
+			issues: []Issue{
+				{Id: "1", SeriesId: "10", Number: "1"},
+				{Id: "2", SeriesId: "10", Number: "1"},
+				{Id: "3", SeriesId: "10", Number: "1", IsVariant: true},
+			},
+			expected: []string{"1:", "2:", "3:"},
+		},
+		"cycle": {
+			issues: []Issue{
+				{Id: "1", OriginalIssueId: "2"},
+				{Id: "2", OriginalIssueId: "1"},
+			},
+			expected: []string{"1: 2"},
+		},
+	} {
+		assert.Equal(t, test.expected, clusterIds(GroupVersions(test.issues)), name)
+	}
+}
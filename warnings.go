@@ -0,0 +1,67 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"io"
+)
+
+// Logs what the library can't return to the caller, such as the warnings from the issues a source fetches.
+// `*log.Logger` implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// A part of a page that couldn't be parsed. The rest of the page is still parsed, so the field is left empty.
+type ParseWarning struct {
+	URL    string // The URL of the page. Only set when the page was fetched by a source.
+	Field  string // The field that couldn't be parsed, such as `cover date`.
+	Text   string // The text from the page.
+	Reason string // Why it couldn't be parsed.
+}
+
+func (w ParseWarning) String() string {
+	if w.URL == "" {
+		return fmt.Sprintf("can't parse %s %q: %s", w.Field, w.Text, w.Reason)
+	}
+	return fmt.Sprintf("can't parse %s %q at URL %s: %s", w.Field, w.Text, w.URL, w.Reason)
+}
+
+// An issue with the warnings from parsing its page.
+type IssueResult struct {
+	Issue    *Issue
+	Warnings []ParseWarning
+}
+
+// Parses an issue page with the warnings for the fields that couldn't be parsed.
+type ExternalIssueResultParser interface {
+	IssueResult(body io.Reader) (*IssueResult, error)
+}
+
+// Logs each warning with the logger. Nothing is logged if the logger is nil.
+func logWarnings(logger Logger, warnings []ParseWarning) {
+	if logger == nil {
+		return
+	}
+	for _, warning := range warnings {
+		logger.Printf("warning: %s", warning)
+	}
+}
+
+// Parses the issue page fetched from the URL and logs its warnings with the URL.
+// Parsers that can't report warnings parse the issue as usual.
+func parseIssue(parser ExternalIssueParser, body io.Reader, url string, logger Logger) (*Issue, error) {
+	resultParser, ok := parser.(ExternalIssueResultParser)
+	if !ok {
+		issue, err := parser.Issue(body)
+		return issue, withUrl(err, url)
+	}
+	result, err := resultParser.IssueResult(body)
+	if err != nil {
+		return nil, withUrl(err, url)
+	}
+	for idx := range result.Warnings {
+		result.Warnings[idx].URL = url
+	}
+	logWarnings(logger, result.Warnings)
+	return result.Issue, nil
+}
@@ -0,0 +1,82 @@
+package externalissuesource
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+// Collects the logged messages.
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestCbParser_IssueResult_Warnings(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue_warnings.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	parser := CbParser{}
+	result, err := parser.IssueResult(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "Astonishing X-Men (2004)", result.Issue.Series)
+	assert.True(t, result.Issue.PublicationDate.IsZero())
+	assert.Equal(t, Unknown, result.Issue.Format)
+	assert.Len(t, result.Warnings, 2)
+	assert.Equal(t, "cover date", result.Warnings[0].Field)
+	assert.Equal(t, "Fall/Winter 2007", result.Warnings[0].Text)
+	assert.NotEmpty(t, result.Warnings[0].Reason)
+	assert.Equal(t, "format", result.Warnings[1].Field)
+	assert.Contains(t, result.Warnings[1].Text, "Motion Comic")
+	assert.Equal(t, "unknown format", result.Warnings[1].Reason)
+
+	file2, err := os.Open("./testdata/cb_issue.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	result, err = parser.IssueResult(file2)
+	assert.Nil(t, err)
+	assert.Len(t, result.Warnings, 0)
+}
+
+func TestCbParser_Issue_Logger(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue_warnings.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	logger := new(testLogger)
+	parser := CbParser{logger: logger}
+	issue, err := parser.Issue(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "22", issue.Number)
+	assert.Len(t, logger.messages, 2)
+	assert.Contains(t, logger.messages[0], `warning: can't parse cover date "Fall/Winter 2007"`)
+}
+
+func TestParseIssue(t *testing.T) {
+	file, err := os.Open("./testdata/cb_issue_warnings.html")
+	defer file.Close()
+	assert.Nil(t, err)
+	logger := new(testLogger)
+	issue, err := parseIssue(&CbParser{}, file, "http://comicbookdb.com/issue.php?ID=103298", logger)
+	assert.Nil(t, err)
+	assert.Equal(t, "22", issue.Number)
+	assert.Len(t, logger.messages, 2)
+	assert.Contains(t, logger.messages[1], "at URL http://comicbookdb.com/issue.php?ID=103298: unknown format")
+
+	// Nothing is logged without a logger.
+	file2, err := os.Open("./testdata/cb_issue_warnings.html")
+	defer file2.Close()
+	assert.Nil(t, err)
+	_, err = parseIssue(&CbParser{}, file2, "http://comicbookdb.com/issue.php?ID=103298", nil)
+	assert.Nil(t, err)
+}
+
+func TestParseWarning_String(t *testing.T) {
+	warning := ParseWarning{Field: "cover date", Text: "Fall/Winter 2007", Reason: "unknown date format"}
+	assert.Equal(t, `can't parse cover date "Fall/Winter 2007": unknown date format`, warning.String())
+	warning.URL = "http://comicbookdb.com/issue.php?ID=1"
+	assert.Equal(t, `can't parse cover date "Fall/Winter 2007" at URL http://comicbookdb.com/issue.php?ID=1: unknown date format`, warning.String())
+}